@@ -2,41 +2,119 @@ package main
 
 import (
 	"fmt"
+	"go/token"
+	"math"
+	"mime"
 	"net/http"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/wzshiming/gotype"
 )
 
 type ServiceCollection struct {
 	PackageName string
+	PackagePath string
 	Services    []*Service
 	Resolvers   []Resolver
 }
 
+// Methods returns the known http methods followed by the custom methods
+// declared by endpoints of the collection.
+func (c *ServiceCollection) Methods() []string {
+	methods := append([]string(nil), HttpMethods...)
+
+	for _, service := range c.Services {
+		for _, endpoint := range service.Endpoints {
+			for _, method := range endpoint.HttpMethods {
+				if method != MethodAny && !containsString(methods, method) {
+					methods = append(methods, method)
+				}
+			}
+		}
+	}
+
+	return methods
+}
+
 func AnalyzePackage(source *SourcePackage) (*ServiceCollection, error) {
 	resolvableTypes, err := analyzeResolvers(source.resolvers)
 	if err != nil {
 		return nil, err
 	}
 
+	mounted, err := findMountedServices(source.Services())
+	if err != nil {
+		return nil, err
+	}
+
 	var services []*Service
 
 	for _, serviceDeclaration := range source.Services() {
-		service, err := analyzeService(serviceDeclaration, resolvableTypes)
+		// Mounted services are only registered below their parent.
+		if mounted[serviceDeclaration.Name()] {
+			continue
+		}
+
+		analyzed, err := analyzeService(serviceDeclaration, nil, resolvableTypes, source)
 		if err != nil {
 			return nil, fmt.Errorf("analyzing service %s: %v",
 				serviceDeclaration.Name(), err)
 		}
 
-		services = append(services, service)
+		services = append(services, analyzed...)
+	}
+
+	if err := validateRoutes(services); err != nil {
+		return nil, err
 	}
 
 	return &ServiceCollection{
 		PackageName: source.Name(),
+		PackagePath: source.Path(),
 		Services:    services,
 		Resolvers:   findUsedResolvers(services),
 	}, nil
 }
 
+// findMountedServices returns the type names of the services, that are
+// mounted into another service. They are only registered below their
+// parents, even if they declare a path on their own. Services mounting
+// themselves, even indirectly, are reported.
+func findMountedServices(decls []ServiceDeclaration) (map[string]bool, error) {
+	var (
+		mounted = make(map[string]bool)
+		visit   func(decl ServiceDeclaration, parents []string) error
+	)
+
+	visit = func(decl ServiceDeclaration, parents []string) error {
+		if containsString(parents, decl.Name()) {
+			return fmt.Errorf("service %s is mounted into itself", decl.Name())
+		}
+
+		for _, child := range decl.Services() {
+			mounted[child.Name()] = true
+
+			if err := visit(child, append(parents, decl.Name())); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, decl := range decls {
+		if err := visit(decl, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return mounted, nil
+}
+
 func findUsedResolvers(services []*Service) []Resolver {
 	var (
 		resolverNameSet = make(map[string]bool)
@@ -62,24 +140,495 @@ func findUsedResolvers(services []*Service) []Resolver {
 }
 
 type Service struct {
-	TypeName  string
-	Path      string
+	TypeName      string
+	FieldName     string   // Name of the field mounting the service, if any
+	Parent        *Service // Service the service is mounted into, if any
+	Path          string   // Path including the path of the parent
+	Endpoints     []*Endpoint
+	Handlers      []*MountedHandler
+	ErrorMappings []ErrorMapping
+}
+
+// MountedHandler is a net/http.Handler provided by a field or method of a
+// service, that handles every request below its path.
+type MountedHandler struct {
+	Service     *Service
+	Name        string // Name of the field or method
+	Method      bool   // Whether the handler is returned by a method
+	Path        string // Path relative to the service
+	FullPath    string // Joined path of the service and the handler
+	StripPrefix bool   // Whether the path is stripped from requests
+}
+
+// Name returns the type name of the service or, if the service is mounted,
+// the fields leading to it, e.g. "ApiService.Users".
+func (s *Service) Name() string {
+	if s.Parent == nil {
+		return s.TypeName
+	}
+
+	return s.Parent.Name() + "." + s.FieldName
+}
+
+// Route is a path of the router and the endpoints registered for it.
+type Route struct {
+	Path      string // Path relative to the service
+	FullPath  string // Joined path of the service and the endpoints
 	Endpoints []*Endpoint
 }
 
+// Routes groups the endpoints of the service by their path.
+func (s *Service) Routes() []Route {
+	var routes []Route
+
+	for _, endpoint := range s.Endpoints {
+		fullPath := joinPath(s.Path, endpoint.Path)
+		found := false
+
+		for i := range routes {
+			if routes[i].FullPath == fullPath {
+				routes[i].Endpoints = append(routes[i].Endpoints, endpoint)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			routes = append(routes, Route{
+				Path:      endpoint.Path,
+				FullPath:  fullPath,
+				Endpoints: []*Endpoint{endpoint},
+			})
+		}
+	}
+
+	return routes
+}
+
+// AnyMethod tests whether an endpoint of the route accepts any method.
+func (r *Route) AnyMethod() bool {
+	for _, endpoint := range r.Endpoints {
+		if endpoint.AnyMethod() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Endpoint returns the endpoint registered for a method.
+func (r *Route) Endpoint(method string) (*Endpoint, bool) {
+	for _, endpoint := range r.Endpoints {
+		if containsString(endpoint.HttpMethods, method) {
+			return endpoint, true
+		}
+	}
+
+	return nil, false
+}
+
+// AllowedMethods returns the methods allowed for the route. HEAD is allowed
+// for GET endpoints and OPTIONS is always allowed.
+func (r *Route) AllowedMethods() []string {
+	var methods []string
+
+	for _, method := range HttpMethods {
+		switch {
+		case method == http.MethodHead && r.hasMethod(http.MethodGet),
+			method == http.MethodOptions,
+			r.hasMethod(method):
+			methods = append(methods, method)
+		}
+	}
+
+	// Custom methods follow in the order of their declaration.
+	for _, endpoint := range r.Endpoints {
+		for _, method := range endpoint.HttpMethods {
+			if method != MethodAny && !containsString(methods, method) {
+				methods = append(methods, method)
+			}
+		}
+	}
+
+	return methods
+}
+
+func (r *Route) hasMethod(method string) bool {
+	_, ok := r.Endpoint(method)
+	return ok
+}
+
+// joinPath joins the path of a service and an endpoint. A trailing slash of
+// the endpoint is kept, so that "/items/" and "/items" are different routes,
+// unless the endpoint path is the root path "/" of the service.
+func joinPath(servicePath, endpointPath string) string {
+	joined := path.Join("/", servicePath, endpointPath)
+
+	if endpointPath != "/" && strings.HasSuffix(endpointPath, "/") && joined != "/" {
+		joined += "/"
+	}
+
+	return joined
+}
+
+// PathParam is a parameter declared in a path, e.g. "{id}". The value can be
+// constrained by a regular expression, e.g. "{id:[0-9]+}", and a catch-all
+// parameter, e.g. "{rest...}" or "*", matches the remainder of the path.
+type PathParam struct {
+	Name     string // Name of the parameter or "*"
+	Pattern  string // Regular expression constraining the value
+	CatchAll bool   // Whether the parameter matches the remainder of the path
+}
+
+// PathPart is either a literal part of a path or a path parameter.
+type PathPart struct {
+	Literal string
+	Param   *PathParam
+}
+
+// pathParamName matches valid names of path parameters. In contrast to go
+// identifiers dashes are allowed.
+var pathParamName = regexp.MustCompile(`^[\w-]+$`)
+
+// splitPath splits a path into literal parts and path parameters.
+func splitPath(fullPath string) ([]PathPart, error) {
+	var (
+		parts []PathPart
+		p     = fullPath
+	)
+
+	for len(p) > 0 {
+		start := strings.IndexAny(p, "{*")
+		if start < 0 {
+			parts = append(parts, PathPart{Literal: p})
+			break
+		}
+
+		if start > 0 {
+			parts = append(parts, PathPart{Literal: p[:start]})
+		}
+
+		var (
+			param PathParam
+			end   int
+		)
+
+		if p[start] == '*' {
+			param = PathParam{Name: "*", CatchAll: true}
+			end = start + 1
+		} else {
+			end = closingBrace(p, start)
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed path parameter in %q", fullPath)
+			}
+
+			param = parsePathParam(p[start+1 : end-1])
+			if !pathParamName.MatchString(param.Name) {
+				return nil, fmt.Errorf("invalid path parameter %q", p[start:end])
+			}
+		}
+
+		if param.Pattern != "" {
+			if _, err := regexp.Compile(param.Pattern); err != nil {
+				return nil, fmt.Errorf("invalid pattern of path parameter {%s}: %v",
+					param.Name, err)
+			}
+		}
+
+		if param.CatchAll && end < len(p) {
+			return nil, fmt.Errorf("catch-all path parameter %s must be the last segment",
+				p[start:end])
+		}
+
+		parts = append(parts, PathPart{Param: &param})
+		p = p[end:]
+	}
+
+	return parts, nil
+}
+
+// closingBrace returns the index after the brace closing the one at start.
+// Braces of the regular expression are balanced.
+func closingBrace(p string, start int) int {
+	depth := 0
+
+	for i := start; i < len(p); i++ {
+		switch p[i] {
+		case '{':
+			depth++
+
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+
+	return -1
+}
+
+func parsePathParam(param string) PathParam {
+	if name := strings.TrimSuffix(param, "..."); name != param {
+		return PathParam{Name: name, CatchAll: true}
+	}
+
+	parts := strings.SplitN(param, ":", 2)
+	if len(parts) > 1 {
+		return PathParam{Name: parts[0], Pattern: parts[1]}
+	}
+
+	return PathParam{Name: param}
+}
+
+// pathParams returns the path parameters of a path.
+func pathParams(p string) ([]PathParam, error) {
+	parts, err := splitPath(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var params []PathParam
+
+	for _, part := range parts {
+		if part.Param != nil {
+			params = append(params, *part.Param)
+		}
+	}
+
+	return params, nil
+}
+
+// formatPath rewrites the path parameters of a path, that was validated
+// during the analysis.
+func formatPath(p string, format func(PathParam) string) string {
+	parts, _ := splitPath(p)
+
+	var b strings.Builder
+
+	for _, part := range parts {
+		if part.Param != nil {
+			b.WriteString(format(*part.Param))
+		} else {
+			b.WriteString(part.Literal)
+		}
+	}
+
+	return b.String()
+}
+
+// routeShape removes the names of the path parameters in a path, so that
+// paths matching the same requests are equal.
+func routeShape(p string) string {
+	return formatPath(p, func(param PathParam) string {
+		switch {
+		case param.CatchAll:
+			return "{...}"
+
+		case param.Pattern != "":
+			return "{:" + param.Pattern + "}"
+
+		default:
+			return "{}"
+		}
+	})
+}
+
+// routeEntry is an endpoint registered for a path in the route table.
+type routeEntry struct {
+	fullPath string
+	endpoint *Endpoint
+}
+
+func (r routeEntry) String() string {
+	return fmt.Sprintf("%s.%s", r.endpoint.Service.Name(), r.endpoint.FuncName)
+}
+
+// validateRoutes reports endpoints, that are registered for the same method
+// and path, as well as endpoints, that are shadowed by an endpoint with the
+// same path except for the names of the path parameters, by another service or
+// by a mounted handler.
+func validateRoutes(services []*Service) error {
+	if err := validateServicePaths(services); err != nil {
+		return err
+	}
+
+	if err := validateHandlers(services); err != nil {
+		return err
+	}
+
+	var entries []routeEntry
+
+	for _, service := range services {
+		for _, endpoint := range service.Endpoints {
+			entry := routeEntry{
+				fullPath: joinPath(service.Path, endpoint.Path),
+				endpoint: endpoint,
+			}
+
+			for _, other := range entries {
+				if routeShape(entry.fullPath) != routeShape(other.fullPath) {
+					continue
+				}
+
+				method, ok := overlappingMethod(entry.endpoint, other.endpoint)
+				if !ok {
+					continue
+				}
+
+				if entry.fullPath == other.fullPath {
+					return fmt.Errorf("duplicate route %s %s: %s and %s",
+						method, entry.fullPath, other, entry)
+				}
+
+				return fmt.Errorf("route %s %s of %s is shadowed by %s of %s",
+					method, entry.fullPath, entry, other.fullPath, other)
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	return nil
+}
+
+// validateServicePaths reports services registered at the same path and
+// endpoints below the path of another service, that is more specific than the
+// path of their own service. Routers like chi route such requests to the
+// other service only.
+func validateServicePaths(services []*Service) error {
+	for i, service := range services {
+		prefix := routeShape(service.Path)
+
+		for _, other := range services[:i] {
+			if routeShape(other.Path) == prefix {
+				return fmt.Errorf("services %s and %s are registered at the same path %s",
+					other.Name(), service.Name(), service.Path)
+			}
+		}
+
+		for _, other := range services {
+			if other == service || isBelowPath(routeShape(other.Path), prefix) {
+				continue
+			}
+
+			for _, endpoint := range other.Endpoints {
+				fullPath := joinPath(other.Path, endpoint.Path)
+
+				if isBelowPath(routeShape(fullPath), prefix) {
+					return fmt.Errorf("route %s of %s.%s is shadowed by the service %s registered at %s",
+						fullPath, other.Name(), endpoint.FuncName, service.Name(), service.Path)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateHandlers reports handlers mounted at the same path and endpoints
+// below the path of a handler.
+func validateHandlers(services []*Service) error {
+	var handlers []*MountedHandler
+
+	for _, service := range services {
+		handlers = append(handlers, service.Handlers...)
+	}
+
+	for i, handler := range handlers {
+		mount := routeShape(handler.FullPath)
+
+		for _, other := range handlers[:i] {
+			if routeShape(other.FullPath) == mount {
+				return fmt.Errorf("duplicate mount %s: %s.%s and %s.%s", handler.FullPath,
+					other.Service.Name(), other.Name, handler.Service.Name(), handler.Name)
+			}
+		}
+
+		for _, service := range services {
+			for _, endpoint := range service.Endpoints {
+				fullPath := joinPath(service.Path, endpoint.Path)
+
+				if isBelowPath(routeShape(fullPath), mount) {
+					return fmt.Errorf("route %s of %s.%s is shadowed by the handler %s.%s mounted at %s",
+						fullPath, service.Name(), endpoint.FuncName,
+						handler.Service.Name(), handler.Name, handler.FullPath)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// isBelowPath tests whether a path equals a prefix or is below it.
+func isBelowPath(p, prefix string) bool {
+	return p == prefix || strings.HasPrefix(p, strings.TrimSuffix(prefix, "/")+"/")
+}
+
+// overlappingMethod returns a method, that both endpoints are registered for.
+func overlappingMethod(a, b *Endpoint) (string, bool) {
+	switch {
+	case a.AnyMethod():
+		return b.HttpMethods[0], true
+
+	case b.AnyMethod():
+		return a.HttpMethods[0], true
+	}
+
+	for _, method := range a.HttpMethods {
+		if containsString(b.HttpMethods, method) {
+			return method, true
+		}
+	}
+
+	return "", false
+}
+
 type Endpoint struct {
-	Service      *Service
-	FuncName     string
-	Path         string
-	HttpMethod   string
-	InputVars    []InputVar
-	InputParams  InputParamSlice
-	ReturnsValue bool
-	ReturnsError bool
+	Service       *Service
+	FuncName      string
+	Path          string
+	HttpMethods   []string // Methods of the endpoint or MethodAny
+	InputVars     []InputVar
+	InputParams   InputParamSlice
+	ReturnsValue  bool
+	ReturnsError  bool
+	Produces      []string    // Media types the returned value is encoded to
+	TextFormat    TextFormat  // Format of the value encoded as plain text
+	CsvColumns    []CsvColumn // Columns of the value encoded as csv
+	StatusCode    int         // Status of a successful response, if not 200
+	Response      *ResponseStruct
+	ErrorMappings []ErrorMapping // Mappings of the endpoint and its service
+	Stream        int            // Kind of stream written as the body, if any
+	Sequence      int            // Kind of sequence, whose values are streamed
+	ContentType   string         // Declared media type of a stream
+}
+
+// ReturnsBody tests whether the endpoint returns a value, that is written as
+// the body of the response.
+func (e *Endpoint) ReturnsBody() bool {
+	return e.ReturnsValue && (e.Response == nil || e.Response.Body != "")
 }
 
 func (e *Endpoint) WrapperFunc() string {
-	return fmt.Sprintf("_handle_%s_%s", e.Service.TypeName, e.FuncName)
+	return fmt.Sprintf("_handle_%s_%s", e.serviceIdent(), e.FuncName)
+}
+
+// AnyMethod tests whether the endpoint accepts any method.
+func (e *Endpoint) AnyMethod() bool {
+	return len(e.HttpMethods) == 1 && e.HttpMethods[0] == MethodAny
+}
+
+// ErrorsFunc returns the name of the function mapping the errors of the
+// endpoint.
+func (e *Endpoint) ErrorsFunc() string {
+	return fmt.Sprintf("_errors_%s_%s", e.serviceIdent(), e.FuncName)
+}
+
+// serviceIdent returns the name of the service usable in an identifier.
+func (e *Endpoint) serviceIdent() string {
+	return strings.Replace(e.Service.Name(), ".", "_", -1)
 }
 
 type Resolver struct {
@@ -160,76 +709,272 @@ const (
 	KindConvertParam // Convert string to builtin type
 	KindResolveParam // Resolve param from other params and/or dependencies
 	KindPayloadParam // Unmarshal payload into param
+	KindQueryParam   // Directly extract param from query string
+	KindHeaderParam  // Directly extract param from request header
+	KindCookieParam  // Directly extract param from request cookie
+	KindSliceParam   // Split string or collect query values into a slice
+	KindFormParam    // Directly extract param from form values
+	KindStructParam  // Assign struct fields from other params
 )
 
 type InputParam struct {
 	ParamKind    int
 	ParamName    string // Name of the param as declared on a method
+	ParamKey     string // Name of the value in the path, query, ...
+	BindingKind  int    // Kind of the param, that extracts the converted value
 	VarName      string // The assigned var name of the resolved value
 	TypeName     string
 	TypePackage  string
+	TypeSlice    bool // Whether the type is a slice of TypeName
 	InputVars    []InputVar
+	Fields       []string // Struct fields assigned from InputVars
 	Resolver     string
 	ReturnsError bool
+	Default      string // Value used if the extracted value is absent
+	Optional     bool   // Whether the value may be absent
+	Required     bool   // Whether an absent value is rejected
+	Validations  []Validation
+	CatchAll     bool        // Whether the path param matches the remaining path
+	Validates    bool        // Whether the type has a Validate method
+	Consumes     []string    // Media types the payload is decoded from
+	Restricted   bool        // Whether the media types are declared explicitly
+	FormFields   []FormField // Struct fields decoded from form values
 }
 
 type InputParamSlice []InputParam
 
-func (i *InputParamSlice) movePayloadLast() {
-	for k, p := range *i {
-		if p.ParamKind == KindPayloadParam {
-			slice := *i
+// ParamBinding describes where the string value of a builtin param is
+// extracted from.
+type ParamBinding struct {
+	ParamKind int
+	ParamKey  string
+	Default   string
+}
 
-			slice = append(slice[:k], slice[k+1:]...)
-			slice = append(slice, p)
+// ParamBindings maps the names of builtin params to their binding. Params
+// without an explicit binding are extracted from the path.
+type ParamBindings map[string]ParamBinding
 
-			*i = slice
-			break
-		}
+// analyzeParamBindings parses the annotations binding builtin params to a
+// part of the request, e.g. "Query: page, size". Every bound name must be an
+// argument of the endpoint.
+func analyzeParamBindings(a Annotations, params []ParamDeclaration) (ParamBindings, error) {
+	var (
+		bindings = make(ParamBindings)
+		names    = make([]string, len(params))
+	)
+
+	for i, param := range params {
+		names[i] = param.Name()
 	}
-}
 
-func (i *InputParamSlice) appendParam(param InputParam) string {
-	param.VarName = fmt.Sprintf("param%d", len(*i))
-	*i = append(*i, param)
-	return param.VarName
-}
+	bind := func(annotation, name string, binding ParamBinding) error {
+		if !containsString(names, name) {
+			return fmt.Errorf("%s: %q is not an argument of the endpoint", annotation, name)
+		}
 
-func (i *InputParamSlice) resolveParams(decls []ParamDeclaration, resolvables ResolvableSlice) ([]InputVar, error) {
-	var inputVars []InputVar
+		bindings[name] = binding
+		return nil
+	}
 
-	for _, decl := range decls {
-		inputVar, err := i.resolveParam(decl, resolvables)
-		if err != nil {
+	for _, name := range a.List(aQuery) {
+		if err := bind("Query", name, ParamBinding{
+			ParamKind: KindQueryParam,
+			ParamKey:  name,
+		}); err != nil {
 			return nil, err
 		}
-
-		inputVars = append(inputVars, *inputVar)
 	}
 
-	return inputVars, nil
-}
+	for _, name := range a.List(aForm) {
+		if err := bind("Form", name, ParamBinding{
+			ParamKind: KindFormParam,
+			ParamKey:  name,
+		}); err != nil {
+			return nil, err
+		}
+	}
 
-func (i *InputParamSlice) resolveParam(decl ParamDeclaration, resolvables ResolvableSlice) (*InputVar, error) {
-	if decl.PointerDepth() > 1 {
-		return nil, fmt.Errorf("%s: pointers of pointers are not supported", decl.Name())
+	for _, pair := range a.Pairs(aHeader) {
+		if err := bind("Header", pair.Name, ParamBinding{
+			ParamKind: KindHeaderParam,
+			ParamKey:  pair.Value,
+		}); err != nil {
+			return nil, err
+		}
 	}
 
-	if v := i.resolveNativeParam(decl); v != nil {
+	for _, pair := range a.Pairs(aCookie) {
+		if err := bind("Cookie", pair.Name, ParamBinding{
+			ParamKind: KindCookieParam,
+			ParamKey:  pair.Value,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, pair := range a.Pairs(aParam) {
+		if err := bind("Param", pair.Name, ParamBinding{
+			ParamKind: KindStringParam,
+			ParamKey:  pair.Value,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, pair := range a.Pairs(aDefault) {
+		binding := bindings.Find(pair.Name)
+		binding.Default = pair.Value
+
+		if err := bind("Default", pair.Name, binding); err != nil {
+			return nil, err
+		}
+	}
+
+	return bindings, nil
+}
+
+var fieldBindingTags = []struct {
+	tag  string
+	kind int
+}{
+	{tPath, KindStringParam},
+	{tQuery, KindQueryParam},
+	{tHeader, KindHeaderParam},
+	{tCookie, KindCookieParam},
+	{tForm, KindFormParam},
+}
+
+// analyzeFieldBinding returns the binding of a struct field declared by
+// its tags, e.g. `query:"page" default:"1"`.
+func analyzeFieldBinding(field ParamDeclaration) (ParamBinding, bool) {
+	tag := field.Tag()
+
+	for _, t := range fieldBindingTags {
+		if key, ok := tag.Lookup(t.tag); ok {
+			if key == "" {
+				key = field.Name()
+			}
+
+			return ParamBinding{
+				ParamKind: t.kind,
+				ParamKey:  key,
+				Default:   tag.Get(tDefault),
+			}, true
+		}
+	}
+
+	return ParamBinding{}, false
+}
+
+var bindingSources = map[int]string{
+	KindStringParam:  "path",
+	KindQueryParam:   "query",
+	KindHeaderParam:  "header",
+	KindCookieParam:  "cookie",
+	KindFormParam:    "form",
+	KindPayloadParam: "body",
+	KindStructParam:  "request",
+}
+
+// BindingSource returns the name of the request part, that a param of the
+// given kind is extracted from.
+func BindingSource(kind int) string {
+	return bindingSources[kind]
+}
+
+func (b ParamBindings) Find(name string) ParamBinding {
+	if binding, ok := b[name]; ok {
+		return binding
+	}
+
+	return ParamBinding{
+		ParamKind: KindStringParam,
+		ParamKey:  name,
+	}
+}
+
+// movePayloadLast moves the payload param and every param depending on it
+// to the end, while preserving the order of the params otherwise.
+func (i *InputParamSlice) movePayloadLast() {
+	var (
+		moved      = make(map[string]bool)
+		head, tail InputParamSlice
+	)
+
+	for _, p := range *i {
+		if p.ParamKind == KindPayloadParam || dependsOnAny(p, moved) {
+			moved[p.VarName] = true
+			tail = append(tail, p)
+		} else {
+			head = append(head, p)
+		}
+	}
+
+	*i = append(head, tail...)
+}
+
+func dependsOnAny(param InputParam, varNames map[string]bool) bool {
+	for _, inputVar := range param.InputVars {
+		if varNames[inputVar.VarName] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (i *InputParamSlice) appendParam(param InputParam) string {
+	param.VarName = fmt.Sprintf("param%d", len(*i))
+	*i = append(*i, param)
+	return param.VarName
+}
+
+func (i *InputParamSlice) resolveParams(decls []ParamDeclaration, resolvables ResolvableSlice, bindings ParamBindings) ([]InputVar, error) {
+	var inputVars []InputVar
+
+	for _, decl := range decls {
+		inputVar, err := i.resolveParam(decl, resolvables, bindings)
+		if err != nil {
+			return nil, err
+		}
+
+		inputVars = append(inputVars, *inputVar)
+	}
+
+	return inputVars, nil
+}
+
+func (i *InputParamSlice) resolveParam(decl ParamDeclaration, resolvables ResolvableSlice, bindings ParamBindings) (*InputVar, error) {
+	if decl.PointerDepth() > 1 {
+		return nil, fmt.Errorf("%s: pointers of pointers are not supported", decl.Name())
+	}
+
+	if v := i.resolveNativeParam(decl); v != nil {
 		return v, nil
 	}
 
 	if rt, ok := resolvables.FindResolver(decl); ok {
-		return i.resolveResolvableParam(decl, rt, resolvables)
+		return i.resolveResolvableParam(decl, rt, resolvables, bindings)
 	}
 
-	if decl.IsBuiltIn() {
-		return i.resolveBuiltinParam(decl)
-	} else {
-		return i.resolvePayloadParam(decl)
+	_, bound := bindings[decl.Name()]
+
+	if decl.IsSlice() && !decl.IsBytes() {
+		if elem := decl.Elem(); bound || elem.IsBuiltIn() || isConvertibleParam(elem) {
+			return i.resolveSliceParam(decl, bindings)
+		}
+	}
+
+	if bound || decl.IsBuiltIn() || isConvertibleParam(decl) {
+		return i.resolveBuiltinParam(decl, bindings)
+	}
+
+	if decl.IsStruct() && isRequestStruct(decl) {
+		return i.resolveStructParam(decl)
 	}
 
-	return nil, fmt.Errorf("could not resolve param '%s %s'", decl.Name(), decl.TypeName())
+	return i.resolvePayloadParam(decl)
 }
 
 func (i *InputParamSlice) resolveNativeParam(decl ParamDeclaration) *InputVar {
@@ -252,7 +997,7 @@ func (i *InputParamSlice) resolveNativeParam(decl ParamDeclaration) *InputVar {
 	return nil
 }
 
-func (i *InputParamSlice) resolveResolvableParam(decl ParamDeclaration, rt *ResolvableType, resolvables ResolvableSlice) (*InputVar, error) {
+func (i *InputParamSlice) resolveResolvableParam(decl ParamDeclaration, rt *ResolvableType, resolvables ResolvableSlice, bindings ParamBindings) (*InputVar, error) {
 	var (
 		exists  bool
 		varName string
@@ -267,7 +1012,7 @@ func (i *InputParamSlice) resolveResolvableParam(decl ParamDeclaration, rt *Reso
 	}
 
 	if !exists {
-		inputVars, err := i.resolveParams(rt.Resolver.InputParams(), resolvables)
+		inputVars, err := i.resolveParams(rt.Resolver.InputParams(), resolvables, bindings)
 		if err != nil {
 			return nil, err
 		}
@@ -289,46 +1034,95 @@ func (i *InputParamSlice) resolveResolvableParam(decl ParamDeclaration, rt *Reso
 	}, nil
 }
 
-func (i *InputParamSlice) resolveBuiltinParam(decl ParamDeclaration) (*InputVar, error) {
+// resolveStringParam returns the var name of the extracted string value for
+// a given binding. An optional param checks for the absence of the value,
+// while a required param rejects requests without the value.
+func (i *InputParamSlice) resolveStringParam(decl ParamDeclaration, binding ParamBinding, optional, required bool) string {
+	var param *InputParam
+
+	for k, p := range *i {
+		if p.ParamKind == binding.ParamKind && p.ParamKey == binding.ParamKey {
+			param = &(*i)[k]
+			break
+		}
+	}
+
+	if param == nil {
+		i.appendParam(InputParam{
+			ParamKind: binding.ParamKind,
+			ParamName: decl.Name(),
+			ParamKey:  binding.ParamKey,
+			TypeName:  "string",
+			Default:   binding.Default,
+		})
+
+		param = &(*i)[len(*i)-1]
+	}
+
+	param.Optional = param.Optional || optional
+	param.Required = param.Required || required
+
+	return param.VarName
+}
+
+// isOptionalParam tests whether a param is declared as a pointer to mark the
+// absence of its value.
+func isOptionalParam(decl ParamDeclaration, binding ParamBinding) bool {
+	return decl.PointerDepth() > 0 && binding.Default == "" &&
+		binding.ParamKind != KindStringParam
+}
+
+// isRequiredBinding tests whether the absence of a value is an error. Only
+// headers and cookies without a default value are required.
+func isRequiredBinding(binding ParamBinding) bool {
+	return binding.Default == "" &&
+		(binding.ParamKind == KindHeaderParam || binding.ParamKind == KindCookieParam)
+}
+
+func (i *InputParamSlice) resolveBuiltinParam(decl ParamDeclaration, bindings ParamBindings) (*InputVar, error) {
 	var (
-		existStringVar  bool
 		existConvertVar bool
-		varName         string
 		kind            int
+		binding         = bindings.Find(decl.Name())
+		optional        = isOptionalParam(decl, binding)
+		pointerDepth    = decl.PointerDepth()
 	)
 
-	if decl.TypeName() == "string" {
-		kind = KindStringParam
-	} else {
-		kind = KindConvertParam
+	typeName, typePackage, err := analyzeConversion(decl)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, p := range *i {
-		if p.ParamKind == KindStringParam && p.ParamName == decl.Name() {
-			varName = p.VarName
-			existStringVar = true
-			break
-		}
+	if err := analyzeDefault(decl, binding, typeName, typePackage, false); err != nil {
+		return nil, err
 	}
 
-	if !existStringVar {
-		varName = i.appendParam(InputParam{
-			ParamKind: KindStringParam,
-			ParamName: decl.Name(),
-			TypeName:  "string",
-		})
+	if typeName == "string" && !optional {
+		kind = KindStringParam
+	} else {
+		kind = KindConvertParam
 	}
 
+	varName := i.resolveStringParam(decl, binding, optional,
+		!optional && isRequiredBinding(binding))
+
 	if kind == KindStringParam {
 		return &InputVar{
 			VarName:      varName,
-			PointerDepth: decl.PointerDepth(),
+			PointerDepth: pointerDepth,
 		}, nil
 	}
 
+	// Optional params are converted into a pointer, that is nil if the value
+	// is absent.
+	if optional {
+		pointerDepth--
+	}
+
 	for _, p := range *i {
-		if p.ParamKind == KindConvertParam &&
-			p.ParamName == decl.Name() && p.TypeName == decl.TypeName() {
+		if p.ParamKind == KindConvertParam && p.InputVars[0].VarName == varName &&
+			p.TypeName == typeName && p.TypePackage == typePackage &&
+			p.Optional == optional {
 			varName = p.VarName
 			existConvertVar = true
 			break
@@ -339,39 +1133,71 @@ func (i *InputParamSlice) resolveBuiltinParam(decl ParamDeclaration) (*InputVar,
 		varName = i.appendParam(InputParam{
 			ParamKind:    kind,
 			ParamName:    decl.Name(),
-			TypeName:     decl.TypeName(),
+			ParamKey:     binding.ParamKey,
+			BindingKind:  binding.ParamKind,
+			TypeName:     typeName,
+			TypePackage:  typePackage,
 			InputVars:    []InputVar{{VarName: varName}},
-			ReturnsError: true,
+			ReturnsError: typeName != "[]byte" && typeName != "string",
+			Optional:     optional,
 		})
 	}
 
 	return &InputVar{
 		VarName:      varName,
-		PointerDepth: decl.PointerDepth(),
+		PointerDepth: pointerDepth,
 	}, nil
 }
 
-func (i *InputParamSlice) resolvePayloadParam(decl ParamDeclaration) (*InputVar, error) {
-	for _, p := range *i {
-		if p.ParamKind == KindPayloadParam {
-			if p.TypeName == decl.TypeName() && p.TypePackage == decl.TypePackage() {
-				return &InputVar{
-					VarName:      p.VarName,
-					PointerDepth: decl.PointerDepth(),
-				}, nil
-			}
+func (i *InputParamSlice) resolveSliceParam(decl ParamDeclaration, bindings ParamBindings) (*InputVar, error) {
+	var (
+		elem      = decl.Elem()
+		binding   = bindings.Find(decl.Name())
+		inputVars []InputVar
+	)
 
-			return nil, fmt.Errorf("cannot resolve param %s, because %s is already claiming the payload",
-				decl.Name(), p.ParamName)
+	if elem.PointerDepth() > 0 {
+		return nil, fmt.Errorf("%s: slices of pointers are not supported", decl.Name())
+	}
+
+	typeName, typePackage, err := analyzeConversion(elem)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := analyzeDefault(decl, binding, typeName, typePackage, true); err != nil {
+		return nil, err
+	}
+
+	for _, p := range *i {
+		if p.ParamKind == KindSliceParam && p.ParamName == decl.Name() &&
+			p.TypeName == typeName && p.TypePackage == typePackage {
+			return &InputVar{
+				VarName:      p.VarName,
+				PointerDepth: decl.PointerDepth(),
+			}, nil
 		}
 	}
 
+	// Query values are collected as is, every other value is split.
+	var defaultValue string
+	if binding.ParamKind != KindQueryParam {
+		inputVars = []InputVar{{VarName: i.resolveStringParam(decl, binding, false, false)}}
+	} else {
+		defaultValue = binding.Default
+	}
+
 	varName := i.appendParam(InputParam{
-		ParamKind:    KindPayloadParam,
+		ParamKind:    KindSliceParam,
 		ParamName:    decl.Name(),
-		TypeName:     decl.TypeName(),
-		TypePackage:  decl.TypePackage(),
+		ParamKey:     binding.ParamKey,
+		BindingKind:  binding.ParamKind,
+		TypeName:     typeName,
+		TypePackage:  typePackage,
+		TypeSlice:    true,
+		InputVars:    inputVars,
 		ReturnsError: true,
+		Default:      defaultValue,
 	})
 
 	return &InputVar{
@@ -380,85 +1206,1536 @@ func (i *InputParamSlice) resolvePayloadParam(decl ParamDeclaration) (*InputVar,
 	}, nil
 }
 
-func analyzeService(decl ServiceDeclaration, resolvables ResolvableSlice) (*Service, error) {
-	var (
-		endpoints []*Endpoint
-		service   = Service{
-			TypeName: decl.Name(),
-			Path:     decl.Path(),
+// analyzeDefault checks the default value of a binding by converting it to
+// the type of the param, so that an invalid default is reported at
+// generation time instead of rejecting every request. The default of a slice
+// is a comma separated list. Path parameters are always present and cannot
+// have a default.
+func analyzeDefault(decl ParamDeclaration, binding ParamBinding, typeName, typePackage string, slice bool) error {
+	if binding.Default == "" {
+		return nil
+	}
+
+	if binding.ParamKind == KindStringParam {
+		return fmt.Errorf("%s: path parameter %s cannot have a default value",
+			decl.Name(), binding.ParamKey)
+	}
+
+	values := []string{binding.Default}
+	if slice {
+		values = strings.Split(binding.Default, ",")
+	}
+
+	for _, value := range values {
+		if err := checkConversion(value, typeName, typePackage); err != nil {
+			return fmt.Errorf("%s: invalid default value %q: %v", decl.Name(), binding.Default, err)
 		}
-	)
+	}
 
-	for _, endpointDeclaration := range decl.Endpoints() {
-		endpoint, err := analyzeEndpoint(endpointDeclaration, resolvables)
-		if err != nil {
-			return nil, fmt.Errorf("analyzing endpoint %s: %v",
-				endpointDeclaration.Name(), err)
+	return nil
+}
+
+// checkConversion converts a string value like the generated code does.
+// Values of text unmarshalers can only be checked at runtime.
+func checkConversion(value, typeName, typePackage string) error {
+	var err error
+
+	switch {
+	case typePackage == pkgTime && typeName == "Time":
+		_, err = time.Parse(time.RFC3339, value)
+
+	case typePackage == pkgTime && typeName == "Duration":
+		_, err = time.ParseDuration(value)
+
+	case typePackage != "":
+		return nil
+
+	case typeName == "bool":
+		_, err = strconv.ParseBool(value)
+
+	case typeName == "float32" || typeName == "float64":
+		_, err = strconv.ParseFloat(value, bitSizes[typeName])
+
+	case strings.HasPrefix(typeName, "uint") || typeName == "byte":
+		_, err = strconv.ParseUint(value, 10, bitSizes[typeName])
+
+	case strings.HasPrefix(typeName, "int") || typeName == "rune":
+		_, err = strconv.ParseInt(value, 10, bitSizes[typeName])
+	}
+
+	if numErr, ok := err.(*strconv.NumError); ok {
+		return numErr.Err
+	}
+
+	return err
+}
+
+// isRequestStruct tests whether any field of a struct param is tagged to be
+// bound to a value of the request.
+func isRequestStruct(decl ParamDeclaration) bool {
+	for _, field := range decl.Fields() {
+		if _, ok := analyzeFieldBinding(field); ok {
+			return true
 		}
 
-		endpoint.Service = &service
-		endpoints = append(endpoints, endpoint)
+		if _, ok := field.Tag().Lookup(tBody); ok {
+			return true
+		}
 	}
 
-	service.Endpoints = endpoints
-	return &service, nil
+	return false
 }
 
-func analyzeEndpoint(decl EndpointDeclaration, resolvables ResolvableSlice) (*Endpoint, error) {
+func (i *InputParamSlice) resolveStructParam(decl ParamDeclaration) (*InputVar, error) {
 	var (
-		inputVars   []InputVar
-		inputParams InputParamSlice
-		httpMethod  = http.MethodGet
+		inputVars []InputVar
+		fields    []string
 	)
 
-	if decl.Annotations().Exists(aMethod) {
-		httpMethod = strings.ToUpper(decl.Annotations().Get(aMethod))
-	}
+	for _, field := range decl.Fields() {
+		var (
+			inputVar *InputVar
+			err      error
+		)
 
-	inputVars, err := inputParams.resolveParams(decl.InputParams(), resolvables)
-	if err != nil {
-		return nil, err
+		if binding, ok := analyzeFieldBinding(field); ok {
+			bindings := ParamBindings{field.Name(): binding}
+
+			if field.IsSlice() && !field.IsBytes() {
+				inputVar, err = i.resolveSliceParam(field, bindings)
+			} else {
+				inputVar, err = i.resolveBuiltinParam(field, bindings)
+			}
+		} else if _, ok := field.Tag().Lookup(tBody); ok {
+			inputVar, err = i.resolvePayloadParam(field)
+		} else {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", decl.Name(), err)
+		}
+
+		inputVars = append(inputVars, *inputVar)
+		fields = append(fields, field.Name())
 	}
 
-	inputParams.movePayloadLast()
+	validations, err := analyzeValidations(decl, func(field ParamDeclaration) (string, string) {
+		if binding, ok := analyzeFieldBinding(field); ok {
+			return BindingSource(binding.ParamKind), binding.ParamKey
+		}
 
-	returnsValue, returnsError, err := analyzeEndpointOutput(decl.OutputParams())
+		return BindingSource(KindPayloadParam), field.Name()
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &Endpoint{
-		FuncName:     decl.Name(),
-		Path:         decl.Path(),
-		HttpMethod:   httpMethod,
-		InputVars:    inputVars,
-		InputParams:  inputParams,
-		ReturnsValue: returnsValue,
-		ReturnsError: returnsError,
+	varName := i.appendParam(InputParam{
+		ParamKind:   KindStructParam,
+		ParamName:   decl.Name(),
+		TypeName:    decl.TypeName(),
+		TypePackage: decl.TypePackage(),
+		InputVars:   inputVars,
+		Fields:      fields,
+		Validations: validations,
+		Validates:   decl.IsValidator(),
+	})
+
+	return &InputVar{
+		VarName:      varName,
+		PointerDepth: decl.PointerDepth(),
 	}, nil
 }
 
-func analyzeEndpointOutput(params []ParamDeclaration) (bool, bool, error) {
-	switch len(params) {
-	case 0:
-		return false, false, nil
+const (
+	_                = iota
+	ValidateRequired // Value must not be zero, empty or nil
+	ValidateMin      // Value or length must not be less than Arg
+	ValidateMax      // Value or length must not be greater than Arg
+)
 
-	case 1:
-		isErr := isErrorParam(params[0])
-		return !isErr, isErr, nil
+const (
+	_               = iota
+	ValidatedInt    // Signed integer compared by value
+	ValidatedUint   // Unsigned integer compared by value
+	ValidatedFloat  // Float compared by value
+	ValidatedLength // String, slice, map or array compared by length
+	ValidatedBool   // Bool, that is required to be true
+	ValidatedZeroer // Type with an IsZero method, e.g. time.Time
+	ValidatedOther  // Any other type, that can only be required as a pointer
+)
 
-	case 2:
-		if !isErrorParam(params[1]) {
-			return false, false, fmt.Errorf(
-				"the second return value must be of type error")
-		}
+// Validation is a single rule of a "validate" struct tag.
+type Validation struct {
+	Rule    int
+	Arg     string
+	Bound   interface{} // Parsed argument, either an int or a float64
+	Field   string
+	Source  string // Request part of the field, e.g. "body"
+	Name    string // Name of the field in the request part
+	Target  int    // What is validated, e.g. ValidatedLength
+	Pointer bool   // Whether the field is a pointer, which may be nil
+}
 
-		return true, true, nil
+var validationRules = map[string]int{
+	"required": ValidateRequired,
+	"min":      ValidateMin,
+	"max":      ValidateMax,
+}
 
-	default:
-		return false, false, fmt.Errorf(
-			"an endpoint can only return 1 or 2 values: either a value, an error or both")
+// analyzeValidations parses the "validate" tags of all struct fields, e.g.
+// `validate:"required,min=1,max=100"`. The request part and name of a field
+// are used for error messages.
+func analyzeValidations(decl ParamDeclaration, nameOf func(ParamDeclaration) (string, string)) ([]Validation, error) {
+	var validations []Validation
+
+	if !decl.IsStruct() {
+		return nil, nil
 	}
+
+	for _, field := range decl.Fields() {
+		tag, ok := field.Tag().Lookup(tValidate)
+		if !ok {
+			continue
+		}
+
+		target, err := validationTarget(field)
+		if err != nil {
+			return nil, err
+		}
+
+		source, name := nameOf(field)
+
+		for _, item := range strings.Split(tag, ",") {
+			var (
+				parts      = strings.SplitN(strings.TrimSpace(item), "=", 2)
+				rule, ok   = validationRules[parts[0]]
+				validation = Validation{
+					Rule:    rule,
+					Field:   field.Name(),
+					Source:  source,
+					Name:    name,
+					Target:  target,
+					Pointer: field.PointerDepth() > 0,
+				}
+			)
+
+			if !ok {
+				return nil, fmt.Errorf("%s: unknown validation rule %q", field.Name(), parts[0])
+			}
+
+			if rule == ValidateRequired && target == ValidatedOther && !validation.Pointer {
+				return nil, fmt.Errorf("%s: cannot require a value of type %s, use a pointer instead",
+					field.Name(), field.TypeString())
+			}
+
+			if rule != ValidateRequired {
+				if len(parts) != 2 {
+					return nil, fmt.Errorf("%s: validation rule %q requires an argument",
+						field.Name(), parts[0])
+				}
+
+				bound, err := parseValidationBound(field, target, parts[1])
+				if err != nil {
+					return nil, fmt.Errorf("%s: validation rule %q %v", field.Name(), parts[0], err)
+				}
+
+				validation.Arg = parts[1]
+				validation.Bound = bound
+			}
+
+			validations = append(validations, validation)
+		}
+	}
+
+	return validations, nil
+}
+
+// validationTarget determines what the rules of a field validate. Numbers
+// are compared by value and types with a length by their length. Any other
+// type only supports the "required" rule.
+func validationTarget(field ParamDeclaration) (int, error) {
+	if field.PointerDepth() > 1 {
+		return 0, fmt.Errorf("%s: pointers of pointers are not supported", field.Name())
+	}
+
+	switch field.Kind() {
+	case gotype.String, gotype.Slice, gotype.Map, gotype.Array:
+		return ValidatedLength, nil
+
+	case gotype.Int, gotype.Int8, gotype.Int16, gotype.Int32, gotype.Int64, gotype.Rune:
+		return ValidatedInt, nil
+
+	case gotype.Uint, gotype.Uint8, gotype.Uint16, gotype.Uint32, gotype.Uint64, gotype.Byte:
+		return ValidatedUint, nil
+
+	case gotype.Float32, gotype.Float64:
+		return ValidatedFloat, nil
+
+	case gotype.Bool:
+		return ValidatedBool, nil
+	}
+
+	if field.IsZeroer() {
+		return ValidatedZeroer, nil
+	}
+
+	return ValidatedOther, nil
+}
+
+var validationBitSizes = map[gotype.Kind]int{
+	gotype.Int8: 8, gotype.Int16: 16, gotype.Int32: 32, gotype.Int64: 64, gotype.Rune: 32,
+	gotype.Uint8: 8, gotype.Uint16: 16, gotype.Uint32: 32, gotype.Uint64: 64, gotype.Byte: 8,
+}
+
+// parseValidationBound parses the argument of a "min" or "max" rule, which
+// must be representable by the type of the field. Lengths are non-negative
+// integers.
+func parseValidationBound(field ParamDeclaration, target int, arg string) (interface{}, error) {
+	bitSize := validationBitSizes[field.Kind()]
+
+	switch target {
+	case ValidatedInt:
+		value, err := strconv.ParseInt(arg, 10, bitSize)
+		if err != nil {
+			return nil, fmt.Errorf("requires an integer argument of type %s", field.TypeString())
+		}
+
+		return int(value), nil
+
+	case ValidatedUint:
+		value, err := strconv.ParseUint(arg, 10, bitSize)
+		if err != nil || value > math.MaxInt64 {
+			return nil, fmt.Errorf("requires a non-negative integer argument of type %s", field.TypeString())
+		}
+
+		return int(value), nil
+
+	case ValidatedLength:
+		value, err := strconv.ParseUint(arg, 10, 0)
+		if err != nil || value > math.MaxInt64 {
+			return nil, fmt.Errorf("requires a non-negative integer argument")
+		}
+
+		return int(value), nil
+
+	case ValidatedFloat:
+		value, err := strconv.ParseFloat(arg, 64)
+		if err != nil || math.IsInf(value, 0) || math.IsNaN(value) {
+			return nil, fmt.Errorf("requires a finite numeric argument")
+		}
+
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("is not supported for type %s", field.TypeString())
+}
+
+var convertibleBuiltins = map[string]bool{
+	"string": true,
+	"bool":   true,
+	"byte":   true,
+	"rune":   true,
+
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+
+	"float32": true, "float64": true,
+}
+
+// analyzeConversion determines the name and package of the type, that the
+// string value of a param is converted to.
+func analyzeConversion(decl ParamDeclaration) (string, string, error) {
+	switch {
+	case decl.IsBytes():
+		return "[]byte", "", nil
+
+	case decl.IsBuiltIn():
+		if convertibleBuiltins[decl.TypeName()] {
+			return decl.TypeName(), "", nil
+		}
+
+	case isTimeParam(decl) || decl.IsTextUnmarshaler():
+		return decl.TypeName(), decl.TypePackage(), nil
+	}
+
+	return "", "", fmt.Errorf("%s: cannot convert string to unsupported type %s",
+		decl.Name(), decl.TypeString())
+}
+
+func isConvertibleParam(decl ParamDeclaration) bool {
+	return decl.IsBytes() || isTimeParam(decl) || decl.IsTextUnmarshaler()
+}
+
+func isTimeParam(decl ParamDeclaration) bool {
+	if decl.TypePackage() != "time" {
+		return false
+	}
+
+	return decl.TypeName() == "Time" || decl.TypeName() == "Duration"
+}
+
+func (i *InputParamSlice) resolvePayloadParam(decl ParamDeclaration) (*InputVar, error) {
+	typeDecl := decl
+
+	if decl.IsSlice() {
+		if typeDecl = decl.Elem(); typeDecl.PointerDepth() > 0 {
+			return nil, fmt.Errorf("%s: slices of pointers are not supported", decl.Name())
+		}
+	}
+
+	for _, p := range *i {
+		if p.ParamKind == KindPayloadParam {
+			if p.TypeName == typeDecl.TypeName() && p.TypePackage == typeDecl.TypePackage() &&
+				p.TypeSlice == decl.IsSlice() {
+				return &InputVar{
+					VarName:      p.VarName,
+					PointerDepth: decl.PointerDepth(),
+				}, nil
+			}
+
+			return nil, fmt.Errorf("cannot resolve param %s, because %s is already claiming the payload",
+				decl.Name(), p.ParamName)
+		}
+	}
+
+	param := InputParam{
+		ParamKind:    KindPayloadParam,
+		ParamName:    decl.Name(),
+		TypeName:     typeDecl.TypeName(),
+		TypePackage:  typeDecl.TypePackage(),
+		TypeSlice:    decl.IsSlice(),
+		ReturnsError: true,
+	}
+
+	if !decl.IsSlice() {
+		validations, err := analyzeValidations(decl, func(field ParamDeclaration) (string, string) {
+			if name := strings.Split(field.Tag().Get(tJson), ",")[0]; name != "" {
+				return BindingSource(KindPayloadParam), name
+			}
+
+			return BindingSource(KindPayloadParam), field.Name()
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		param.Validations = validations
+		param.Validates = decl.IsValidator()
+	}
+
+	param.Consumes = []string{MediaJson, MediaXml}
+
+	if decl.IsStruct() {
+		param.Consumes = append(param.Consumes, MediaForm, MediaMultipart)
+		param.FormFields = analyzeFormFields(decl)
+	}
+
+	varName := i.appendParam(param)
+
+	return &InputVar{
+		VarName:      varName,
+		PointerDepth: decl.PointerDepth(),
+	}, nil
+}
+
+const (
+	MediaJson      = "application/json"
+	MediaXml       = "application/xml"
+	MediaTextXml   = "text/xml"
+	MediaForm      = "application/x-www-form-urlencoded"
+	MediaMultipart = "multipart/form-data"
+)
+
+// consumableMediaTypes maps the media types accepted by a "Consumes"
+// annotation to the media type of the decoder, that is used for them.
+var consumableMediaTypes = map[string]string{
+	MediaJson:      MediaJson,
+	MediaXml:       MediaXml,
+	MediaTextXml:   MediaXml,
+	MediaForm:      MediaForm,
+	MediaMultipart: MediaMultipart,
+}
+
+// analyzeConsumes restricts the media types, that the payload is decoded
+// from, to the ones declared by a "Consumes" annotation.
+func (i InputParamSlice) analyzeConsumes(mediaTypes []string) error {
+	for k, p := range i {
+		if p.ParamKind != KindPayloadParam {
+			continue
+		}
+
+		if len(mediaTypes) == 0 {
+			return nil
+		}
+
+		var consumes []string
+
+		for _, mediaType := range mediaTypes {
+			mediaType = strings.ToLower(mediaType)
+
+			decoder, ok := consumableMediaTypes[mediaType]
+			if !ok {
+				return fmt.Errorf("unsupported media type %q", mediaType)
+			}
+
+			if !containsString(p.Consumes, decoder) {
+				typeName := p.TypeName
+				if p.TypeSlice {
+					typeName = "[]" + typeName
+				}
+
+				return fmt.Errorf("%s: cannot decode %s from %s",
+					p.ParamName, typeName, mediaType)
+			}
+
+			consumes = append(consumes, mediaType)
+		}
+
+		i[k].Consumes = consumes
+		i[k].Restricted = true
+		return nil
+	}
+
+	if len(mediaTypes) > 0 {
+		return fmt.Errorf("consumed media types are declared, but there is no payload")
+	}
+
+	return nil
+}
+
+// FormField is a field of a payload struct, that is decoded from a form
+// value.
+type FormField struct {
+	Field       string
+	Key         string
+	TypeName    string
+	TypePackage string
+	Slice       bool
+	Pointer     bool
+}
+
+// analyzeFormFields determines the fields of a payload struct, that can be
+// decoded from form values. The name of a value is taken from the "json" tag
+// of a field, like it is for json payloads. Fields of types, that cannot be
+// converted from a string, are skipped.
+func analyzeFormFields(decl ParamDeclaration) []FormField {
+	var formFields []FormField
+
+	for _, field := range decl.Fields() {
+		key := strings.Split(field.Tag().Get(tJson), ",")[0]
+
+		if key == "-" || !token.IsExported(field.Name()) {
+			continue
+		}
+
+		if key == "" {
+			key = field.Name()
+		}
+
+		var (
+			typeDecl = field
+			slice    = field.IsSlice() && !field.IsBytes()
+		)
+
+		if slice {
+			typeDecl = field.Elem()
+		}
+
+		if field.PointerDepth() > 1 || (slice && field.PointerDepth()+typeDecl.PointerDepth() > 0) {
+			continue
+		}
+
+		typeName, typePackage, err := analyzeConversion(typeDecl)
+		if err != nil {
+			continue
+		}
+
+		formFields = append(formFields, FormField{
+			Field:       field.Name(),
+			Key:         key,
+			TypeName:    typeName,
+			TypePackage: typePackage,
+			Slice:       slice,
+			Pointer:     field.PointerDepth() > 0,
+		})
+	}
+
+	return formFields
+}
+
+func containsString(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+
+	return false
+}
+
+// analyzeService returns the service followed by the services mounted into
+// it. Mounted services inherit the error mappings of their parent.
+func analyzeService(decl ServiceDeclaration, parent *Service, resolvables ResolvableSlice, source *SourcePackage) ([]*Service, error) {
+	var (
+		endpoints []*Endpoint
+		service   = Service{
+			TypeName: decl.Name(),
+			Path:     decl.Path(),
+		}
+	)
+
+	errorMappings, err := analyzeErrorMappings(decl.Annotations(), source)
+	if err != nil {
+		return nil, err
+	}
+
+	if parent != nil {
+		service.FieldName = decl.FieldName()
+		service.Parent = parent
+		service.Path = joinPath(joinPath(parent.Path, decl.Mount()), decl.Path())
+		errorMappings = mergeErrorMappings(errorMappings, parent.ErrorMappings)
+	}
+
+	service.ErrorMappings = errorMappings
+
+	for _, endpointDeclaration := range decl.Endpoints() {
+		endpoint, err := analyzeEndpoint(endpointDeclaration, resolvables, source, errorMappings)
+		if err != nil {
+			return nil, fmt.Errorf("analyzing endpoint %s: %v",
+				endpointDeclaration.Name(), err)
+		}
+
+		if err := endpoint.analyzePathParams(joinPath(service.Path, endpoint.Path)); err != nil {
+			return nil, fmt.Errorf("analyzing endpoint %s: %v",
+				endpointDeclaration.Name(), err)
+		}
+
+		endpoint.Service = &service
+		endpoints = append(endpoints, endpoint)
+	}
+
+	service.Endpoints = endpoints
+
+	for _, handlerDeclaration := range decl.Handlers() {
+		handler, err := analyzeHandler(handlerDeclaration, &service)
+		if err != nil {
+			return nil, fmt.Errorf("analyzing handler %s: %v",
+				handlerDeclaration.Name(), err)
+		}
+
+		service.Handlers = append(service.Handlers, handler)
+	}
+
+	services := []*Service{&service}
+
+	for _, child := range decl.Services() {
+		mounted, err := analyzeService(child, &service, resolvables, source)
+		if err != nil {
+			return nil, fmt.Errorf("analyzing service %s: %v",
+				child.FieldName(), err)
+		}
+
+		services = append(services, mounted...)
+	}
+
+	return services, nil
+}
+
+// analyzePathParams ensures, that every path parameter of the joined path is
+// bound to an argument and every argument bound to the path has a matching
+// path parameter. Otherwise the value would silently be empty.
+func (e *Endpoint) analyzePathParams(fullPath string) error {
+	pathParams, err := pathParams(fullPath)
+	if err != nil {
+		return err
+	}
+
+	for i, param := range e.InputParams {
+		if param.ParamKind != KindStringParam {
+			continue
+		}
+
+		pathParam, ok := findPathParam(pathParams, param.ParamKey)
+		if !ok {
+			return fmt.Errorf("argument %s has no matching path parameter {%s} in %s",
+				param.ParamName, param.ParamKey, fullPath)
+		}
+
+		e.InputParams[i].CatchAll = pathParam.CatchAll
+	}
+
+	for _, pathParam := range pathParams {
+		if !e.bindsPathParam(pathParam.Name) {
+			return fmt.Errorf("path parameter {%s} of %s is not bound to an argument",
+				pathParam.Name, fullPath)
+		}
+
+		if pathParam.CatchAll && e.convertsPathParam(pathParam.Name) {
+			return fmt.Errorf("catch-all path parameter {%s} must be bound to a string argument",
+				pathParam.Name)
+		}
+	}
+
+	return nil
+}
+
+func findPathParam(pathParams []PathParam, name string) (PathParam, bool) {
+	for _, pathParam := range pathParams {
+		if pathParam.Name == name {
+			return pathParam, true
+		}
+	}
+
+	return PathParam{}, false
+}
+
+func (e *Endpoint) bindsPathParam(name string) bool {
+	for _, param := range e.InputParams {
+		if param.ParamKind == KindStringParam && param.ParamKey == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// convertsPathParam tests whether the value of a path parameter is converted
+// to a type other than string.
+func (e *Endpoint) convertsPathParam(name string) bool {
+	for _, param := range e.InputParams {
+		if param.ParamKind != KindStringParam && param.BindingKind == KindStringParam &&
+			param.ParamKey == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// analyzeHandler analyzes a handler mounted into a service. By default the
+// path of the handler is stripped from requests, so that e.g. a file server
+// sees paths relative to the mount path.
+func analyzeHandler(decl HandlerDeclaration, service *Service) (*MountedHandler, error) {
+	handler := MountedHandler{
+		Service:     service,
+		Name:        decl.Name(),
+		Method:      decl.IsMethod(),
+		Path:        joinPath("", decl.Mount()),
+		FullPath:    joinPath(service.Path, decl.Mount()),
+		StripPrefix: true,
+	}
+
+	if handler.Method {
+		out := decl.OutputParams()
+		if len(decl.InputParams()) != 0 || len(out) != 1 || !out[0].IsHandler() {
+			return nil, fmt.Errorf("a mounted method must return a net/http.Handler without any arguments")
+		}
+	} else if !decl.IsHandler() {
+		return nil, fmt.Errorf("a mounted field must be a service or implement net/http.Handler")
+	}
+
+	if decl.Annotations().Exists(aStripPrefix) {
+		strip, err := strconv.ParseBool(decl.Annotations().Get(aStripPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of Strip-Prefix %q",
+				decl.Annotations().Get(aStripPrefix))
+		}
+
+		handler.StripPrefix = strip
+	}
+
+	params, err := pathParams(handler.FullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, param := range params {
+		if param.CatchAll {
+			return nil, fmt.Errorf("the path %s cannot contain the catch-all path parameter {%s}",
+				handler.FullPath, param.Name)
+		}
+	}
+
+	if len(params) > 0 && handler.StripPrefix {
+		return nil, fmt.Errorf("the path %s cannot be stripped, because it contains path parameters",
+			handler.FullPath)
+	}
+
+	return &handler, nil
+}
+
+func analyzeEndpoint(decl EndpointDeclaration, resolvables ResolvableSlice, source *SourcePackage, serviceMappings []ErrorMapping) (*Endpoint, error) {
+	var (
+		inputVars   []InputVar
+		inputParams InputParamSlice
+	)
+
+	httpMethods, err := analyzeHttpMethods(decl.Annotations())
+	if err != nil {
+		return nil, err
+	}
+
+	bindings, err := analyzeParamBindings(decl.Annotations(), decl.InputParams())
+	if err != nil {
+		return nil, err
+	}
+
+	inputVars, err = inputParams.resolveParams(decl.InputParams(), resolvables, bindings)
+	if err != nil {
+		return nil, err
+	}
+
+	inputParams.movePayloadLast()
+
+	if err := inputParams.analyzeConsumes(decl.Annotations().List(aConsumes)); err != nil {
+		return nil, err
+	}
+
+	returnsValue, returnsError, err := analyzeEndpointOutput(decl.OutputParams())
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := Endpoint{
+		FuncName:     decl.Name(),
+		Path:         decl.Path(),
+		HttpMethods:  httpMethods,
+		InputVars:    inputVars,
+		InputParams:  inputParams,
+		ReturnsValue: returnsValue,
+		ReturnsError: returnsError,
+	}
+
+	body, err := endpoint.analyzeResponse(decl.OutputParams())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := endpoint.analyzeStream(decl, body); err != nil {
+		return nil, err
+	}
+
+	if endpoint.Stream == 0 {
+		if err := endpoint.analyzeProduces(decl, body); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := endpoint.analyzeStatus(decl); err != nil {
+		return nil, err
+	}
+
+	errorMappings, err := analyzeErrorMappings(decl.Annotations(), source)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(errorMappings) > 0 && !returnsError {
+		return nil, fmt.Errorf("errors are mapped, but the endpoint does not return an error")
+	}
+
+	if returnsError {
+		endpoint.ErrorMappings = mergeErrorMappings(errorMappings, serviceMappings)
+	}
+
+	return &endpoint, nil
+}
+
+// MethodAny is declared for endpoints accepting any method.
+const MethodAny = "ANY"
+
+// HttpMethods are the known http methods including the extensions of WebDAV.
+// Endpoints can declare other methods as well.
+var HttpMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+	"PROPFIND",
+	"PROPPATCH",
+	"MKCOL",
+	"COPY",
+	"MOVE",
+	"LOCK",
+	"UNLOCK",
+	"REPORT",
+	"SEARCH",
+}
+
+// analyzeHttpMethods parses a "Method" annotation, e.g. "Method: GET, HEAD".
+// Endpoints without the annotation default to GET.
+func analyzeHttpMethods(a Annotations) ([]string, error) {
+	var (
+		declared = a.List(aMethod)
+		methods  []string
+	)
+
+	if len(declared) == 0 {
+		return []string{http.MethodGet}, nil
+	}
+
+	for _, method := range declared {
+		method = strings.ToUpper(method)
+
+		switch {
+		case method == MethodAny:
+			if len(declared) > 1 {
+				return nil, fmt.Errorf("method %s cannot be combined with other methods", MethodAny)
+			}
+
+		case !isToken(method):
+			return nil, fmt.Errorf("invalid http method %q", method)
+
+		case containsString(methods, method):
+			return nil, fmt.Errorf("method %s is declared more than once", method)
+		}
+
+		methods = append(methods, method)
+	}
+
+	return methods, nil
+}
+
+// isToken tests whether a method is a token as defined by RFC 7230.
+func isToken(method string) bool {
+	if method == "" {
+		return false
+	}
+
+	for _, c := range method {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", c):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func analyzeEndpointOutput(params []ParamDeclaration) (bool, bool, error) {
+	switch len(params) {
+	case 0:
+		return false, false, nil
+
+	case 1:
+		isErr := isErrorParam(params[0])
+		return !isErr, isErr, nil
+
+	case 2:
+		if !isErrorParam(params[1]) {
+			return false, false, fmt.Errorf(
+				"the second return value must be of type error")
+		}
+
+		return true, true, nil
+
+	default:
+		return false, false, fmt.Errorf(
+			"an endpoint can only return 1 or 2 values: either a value, an error or both")
+	}
+}
+
+// analyzeStatus determines the status code of a successful response, which is
+// declared by a "Status" annotation. Endpoints without a return value default
+// to 204 No Content, unless they write the response themselves.
+func (e *Endpoint) analyzeStatus(decl EndpointDeclaration) error {
+	if !decl.Annotations().Exists(aStatus) {
+		if !e.ReturnsBody() && !e.WritesResponse() {
+			e.StatusCode = http.StatusNoContent
+		}
+
+		return nil
+	}
+
+	status := decl.Annotations().Get(aStatus)
+
+	code, err := strconv.Atoi(status)
+	if err != nil || http.StatusText(code) == "" {
+		return fmt.Errorf("invalid status code %q", status)
+	}
+
+	if e.WritesResponse() {
+		return fmt.Errorf("a status code cannot be declared, if the endpoint writes the response itself")
+	}
+
+	if e.ReturnsBody() && !bodyAllowedForStatus(code) {
+		return fmt.Errorf("status code %d does not allow a response body", code)
+	}
+
+	if code != http.StatusOK {
+		e.StatusCode = code
+	}
+
+	return nil
+}
+
+// WritesResponse tests whether the response writer is passed to the endpoint
+// or any of its resolvers.
+func (e *Endpoint) WritesResponse() bool {
+	for _, inputVar := range e.InputVars {
+		if inputVar.VarName == "w" {
+			return true
+		}
+	}
+
+	for _, param := range e.InputParams {
+		for _, inputVar := range param.InputVars {
+			if inputVar.VarName == "w" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func bodyAllowedForStatus(code int) bool {
+	return code >= 200 && code != http.StatusNoContent && code != http.StatusNotModified
+}
+
+// ErrorMapping maps a package-level error variable to a status code.
+type ErrorMapping struct {
+	VarPackage string // Import path of the variable, if declared by another package
+	VarName    string
+	StatusCode int
+}
+
+// analyzeErrorMappings parses an "Errors" annotation, e.g.
+// "Errors: ErrNotFound=404, ErrConflict=409".
+func analyzeErrorMappings(a Annotations, source *SourcePackage) ([]ErrorMapping, error) {
+	var errorMappings []ErrorMapping
+
+	for _, pair := range a.Pairs(aErrors) {
+		varPackage, varName, ok := source.LookupErrorVar(pair.Name)
+		if !ok {
+			return nil, fmt.Errorf("cannot map %s, because it is not an error variable of the package or its imports",
+				pair.Name)
+		}
+
+		code, err := strconv.Atoi(pair.Value)
+		if err != nil || http.StatusText(code) == "" || code < http.StatusBadRequest {
+			return nil, fmt.Errorf("cannot map %s to invalid error status code %q",
+				pair.Name, pair.Value)
+		}
+
+		errorMappings = append(errorMappings, ErrorMapping{
+			VarPackage: varPackage,
+			VarName:    varName,
+			StatusCode: code,
+		})
+	}
+
+	return errorMappings, nil
+}
+
+// mergeErrorMappings appends the mappings of a service to the ones of an
+// endpoint. Mappings of the endpoint take precedence.
+func mergeErrorMappings(endpointMappings, serviceMappings []ErrorMapping) []ErrorMapping {
+	merged := endpointMappings
+
+	for _, serviceMapping := range serviceMappings {
+		exists := false
+
+		for _, endpointMapping := range endpointMappings {
+			if endpointMapping.VarPackage == serviceMapping.VarPackage &&
+				endpointMapping.VarName == serviceMapping.VarName {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			merged = append(merged, serviceMapping)
+		}
+	}
+
+	return merged
+}
+
+const (
+	_                = iota
+	StreamReader     // io.Reader copied to the response
+	StreamReadCloser // io.ReadCloser copied to the response and closed
+	StreamFile       // fs.File served if seekable and closed
+	StreamOsFile     // *os.File served and closed
+	StreamEvents     // Sequence written as server-sent events
+	StreamNdjson     // Sequence written as newline delimited json
+)
+
+const (
+	StreamFormatEvents = "events"
+	StreamFormatNdjson = "ndjson"
+
+	MediaEvents = "text/event-stream"
+	MediaNdjson = "application/x-ndjson"
+)
+
+// analyzeStream determines whether the returned value is streamed to the
+// response instead of being encoded. The media type of a stream is declared
+// by a "Content-Type" annotation. Values received from channels and iterators
+// are sent as events, unless a "Stream" annotation declares otherwise.
+func (e *Endpoint) analyzeStream(decl EndpointDeclaration, output *ParamDeclaration) error {
+	var (
+		contentType = decl.Annotations().Get(aContentType)
+		format      = strings.ToLower(decl.Annotations().Get(aStream))
+	)
+
+	if output != nil {
+		e.Stream = analyzeStreamKind(*output)
+		e.Sequence = analyzeSequence(*output)
+
+		switch output.Kind() {
+		case gotype.Chan, gotype.Func:
+			if e.Sequence == 0 {
+				return fmt.Errorf("cannot stream values of %s", output.TypeString())
+			}
+		}
+	}
+
+	switch format {
+	case "":
+		// Slices are only streamed on demand.
+		if e.Sequence == SequenceSlice {
+			e.Sequence = 0
+		} else if e.Sequence != 0 {
+			e.Stream = StreamEvents
+		}
+
+	case StreamFormatEvents, StreamFormatNdjson:
+		if e.Sequence == 0 {
+			return fmt.Errorf("only slices, channels and iterators can be streamed as %s", format)
+		}
+
+		e.Stream = StreamEvents
+		if format == StreamFormatNdjson {
+			e.Stream = StreamNdjson
+		}
+
+	default:
+		return fmt.Errorf("unsupported stream format %q", format)
+	}
+
+	switch e.Stream {
+	case 0:
+		if contentType != "" {
+			return fmt.Errorf("a content type can only be declared for streamed responses")
+		}
+
+		return nil
+
+	case StreamEvents, StreamNdjson:
+		if contentType != "" || decl.Annotations().Exists(aProduces) {
+			mediaType := MediaEvents
+			if e.Stream == StreamNdjson {
+				mediaType = MediaNdjson
+			}
+
+			return fmt.Errorf("the media type of the stream cannot be declared, it is always %s", mediaType)
+		}
+
+		return nil
+	}
+
+	if decl.Annotations().Exists(aProduces) {
+		return fmt.Errorf("the media type of a stream must be declared as Content-Type")
+	}
+
+	if contentType != "" {
+		if _, _, err := mime.ParseMediaType(contentType); err != nil {
+			return fmt.Errorf("invalid content type %q: %v", contentType, err)
+		}
+	}
+
+	e.ContentType = contentType
+	return nil
+}
+
+func analyzeStreamKind(decl ParamDeclaration) int {
+	var (
+		typePackage = decl.TypePackage()
+		typeName    = decl.TypeName()
+	)
+
+	switch decl.PointerDepth() {
+	case 0:
+		switch {
+		case typePackage == "io/fs" && typeName == "File":
+			return StreamFile
+		case typePackage == "io" && typeName == "ReadCloser":
+			return StreamReadCloser
+		case typePackage == "io" && typeName == "Reader":
+			return StreamReader
+		}
+
+	case 1:
+		if typePackage == "os" && typeName == "File" {
+			return StreamOsFile
+		}
+	}
+
+	return 0
+}
+
+const (
+	_                = iota
+	SequenceChan     // Values are received from a channel
+	SequenceIterator // Values are yielded by a function
+	SequenceSlice    // Values are elements of a slice
+)
+
+func analyzeSequence(decl ParamDeclaration) int {
+	switch {
+	case decl.PointerDepth() > 0:
+		return 0
+	case decl.IsSlice() && !decl.IsBytes():
+		return SequenceSlice
+	case decl.IsReceiveChan():
+		return SequenceChan
+	case decl.IsIterator():
+		return SequenceIterator
+	}
+
+	return 0
+}
+
+const (
+	MediaText = "text/plain"
+	MediaCsv  = "text/csv"
+)
+
+// analyzeProduces determines the media types, that the returned value can be
+// encoded to. A "Produces" annotation restricts them to the declared ones.
+func (e *Endpoint) analyzeProduces(decl EndpointDeclaration, output *ParamDeclaration) error {
+	mediaTypes := decl.Annotations().List(aProduces)
+
+	if output == nil {
+		if len(mediaTypes) > 0 {
+			return fmt.Errorf("produced media types are declared, but there is no response body")
+		}
+
+		return nil
+	}
+
+	producible := []string{MediaJson}
+
+	if output.Kind() != gotype.Map {
+		producible = append(producible, MediaXml)
+	}
+
+	if format, ok := analyzeTextFormat(*output); ok && output.PointerDepth() == 0 {
+		producible = append(producible, MediaText)
+		e.TextFormat = format
+	}
+
+	if columns := analyzeCsvColumns(*output); len(columns) > 0 {
+		producible = append(producible, MediaCsv)
+		e.CsvColumns = columns
+	}
+
+	if len(mediaTypes) == 0 {
+		e.Produces = producible
+		return nil
+	}
+
+	for _, mediaType := range mediaTypes {
+		mediaType = strings.ToLower(mediaType)
+
+		switch mediaType {
+		case MediaJson, MediaXml, MediaText, MediaCsv:
+		default:
+			return fmt.Errorf("unsupported media type %q", mediaType)
+		}
+
+		if !containsString(producible, mediaType) {
+			return fmt.Errorf("cannot encode %s as %s", output.TypeString(), mediaType)
+		}
+
+		e.Produces = append(e.Produces, mediaType)
+	}
+
+	return nil
+}
+
+const (
+	_              = iota
+	FormatString   // Convert to string
+	FormatBool     // Format using strconv.FormatBool
+	FormatInt      // Format using strconv.FormatInt
+	FormatUint     // Format using strconv.FormatUint
+	FormatFloat    // Format using strconv.FormatFloat
+	FormatTime     // Format time.Time as RFC3339
+	FormatStringer // Call the String method
+	FormatHttpTime // Format time.Time as used in HTTP headers
+)
+
+// TextFormat describes how a value is formatted as text.
+type TextFormat struct {
+	Format  int
+	BitSize int
+}
+
+// analyzeTextFormat determines the format of a value as text. Only numbers,
+// strings, booleans, times and types with a String method are supported.
+func analyzeTextFormat(decl ParamDeclaration) (TextFormat, bool) {
+	if isTimeParam(decl) && decl.TypeName() == "Time" {
+		return TextFormat{Format: FormatTime}, true
+	}
+
+	if decl.IsStringer() {
+		return TextFormat{Format: FormatStringer}, true
+	}
+
+	switch decl.Kind() {
+	case gotype.String:
+		return TextFormat{Format: FormatString}, true
+
+	case gotype.Bool:
+		return TextFormat{Format: FormatBool}, true
+
+	case gotype.Int, gotype.Int8, gotype.Int16, gotype.Int32, gotype.Int64, gotype.Rune:
+		return TextFormat{Format: FormatInt}, true
+
+	case gotype.Uint, gotype.Uint8, gotype.Uint16, gotype.Uint32, gotype.Uint64, gotype.Byte:
+		return TextFormat{Format: FormatUint}, true
+
+	case gotype.Float32:
+		return TextFormat{Format: FormatFloat, BitSize: 32}, true
+
+	case gotype.Float64:
+		return TextFormat{Format: FormatFloat, BitSize: 64}, true
+	}
+
+	return TextFormat{}, false
+}
+
+// CsvColumn is a field of a struct, that is written as a column of a csv
+// record.
+type CsvColumn struct {
+	TextFormat
+	Field   string
+	Name    string
+	Pointer bool
+}
+
+// analyzeCsvColumns determines the columns of a slice of structs encoded as
+// csv. The name of a column is taken from the "json" tag of a field. Fields,
+// that cannot be formatted as text, are skipped.
+func analyzeCsvColumns(decl ParamDeclaration) []CsvColumn {
+	if !decl.IsSlice() || decl.PointerDepth() > 0 {
+		return nil
+	}
+
+	row := decl.Elem()
+	if !row.IsStruct() || row.PointerDepth() > 0 {
+		return nil
+	}
+
+	var columns []CsvColumn
+
+	for _, field := range row.Fields() {
+		name := strings.Split(field.Tag().Get(tJson), ",")[0]
+
+		if name == "-" || !token.IsExported(field.Name()) || field.PointerDepth() > 1 {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name()
+		}
+
+		if format, ok := analyzeTextFormat(field); ok {
+			columns = append(columns, CsvColumn{
+				TextFormat: format,
+				Field:      field.Name(),
+				Name:       name,
+				Pointer:    field.PointerDepth() > 0,
+			})
+		}
+	}
+
+	return columns
+}
+
+const (
+	_               = iota
+	ResponseStatus  // Field holds the status code
+	ResponseHeader  // Field holds the value of a header
+	ResponseHeaders // Field holds a http.Header
+	ResponseCookie  // Field holds a http.Cookie
+)
+
+// ResponseField is a field of a response struct, that is written to the
+// response besides the body.
+type ResponseField struct {
+	TextFormat
+	ResponseKind int
+	Field        string
+	Name         string // Name of the header
+	Slice        bool
+	Pointer      bool
+}
+
+// ResponseStruct is a returned struct, whose tagged fields describe the
+// response.
+type ResponseStruct struct {
+	Body   string // Name of the field holding the body
+	Fields []ResponseField
+}
+
+// analyzeResponse determines the declaration of the response body. If a
+// response struct is returned, the body is taken from its field tagged with
+// "body".
+func (e *Endpoint) analyzeResponse(params []ParamDeclaration) (*ParamDeclaration, error) {
+	if !e.ReturnsValue {
+		return nil, nil
+	}
+
+	output := params[0]
+
+	if !output.IsStruct() || !isResponseStruct(output) {
+		return &output, nil
+	}
+
+	if output.PointerDepth() > 0 {
+		return nil, fmt.Errorf("response struct %s must be returned as a value",
+			output.TypeString())
+	}
+
+	var (
+		body     *ParamDeclaration
+		response ResponseStruct
+	)
+
+	for _, field := range output.Fields() {
+		tag := field.Tag()
+
+		if _, ok := tag.Lookup(tBody); ok {
+			if body != nil {
+				return nil, fmt.Errorf("%s: multiple fields are tagged as body", output.TypeName())
+			}
+
+			field := field
+			body = &field
+			response.Body = field.Name()
+			continue
+		}
+
+		responseField, ok, err := analyzeResponseField(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", output.TypeName(), err)
+		}
+
+		if ok {
+			response.Fields = append(response.Fields, responseField)
+		}
+	}
+
+	e.Response = &response
+	return body, nil
+}
+
+// isResponseStruct tests whether any field of a struct is tagged to be
+// written to the response.
+func isResponseStruct(decl ParamDeclaration) bool {
+	for _, field := range decl.Fields() {
+		for _, tag := range []string{tBody, tStatus, tHeader, tCookie} {
+			if _, ok := field.Tag().Lookup(tag); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// analyzeResponseField determines how a field of a response struct is
+// written, e.g. `header:"Location"`.
+func analyzeResponseField(field ParamDeclaration) (ResponseField, bool, error) {
+	var (
+		tag           = field.Tag()
+		responseField = ResponseField{
+			Field:   field.Name(),
+			Pointer: field.PointerDepth() > 0,
+		}
+	)
+
+	if field.PointerDepth() > 1 {
+		return responseField, false, fmt.Errorf("%s: pointers of pointers are not supported",
+			field.Name())
+	}
+
+	if _, ok := tag.Lookup(tStatus); ok {
+		if !field.IsBuiltIn() || field.TypeName() != "int" || field.PointerDepth() > 0 {
+			return responseField, false, fmt.Errorf("%s: status code must be of type int",
+				field.Name())
+		}
+
+		responseField.ResponseKind = ResponseStatus
+		return responseField, true, nil
+	}
+
+	if name, ok := tag.Lookup(tHeader); ok {
+		if isHttpType(field, "Header") {
+			responseField.ResponseKind = ResponseHeaders
+			return responseField, true, nil
+		}
+
+		if name == "" {
+			name = field.Name()
+		}
+
+		typeDecl := field
+		if responseField.Slice = field.IsSlice() && !field.IsBytes(); responseField.Slice {
+			typeDecl = field.Elem()
+		}
+
+		format, ok := analyzeTextFormat(typeDecl)
+		if !ok || (responseField.Slice && (responseField.Pointer || typeDecl.PointerDepth() > 0)) {
+			return responseField, false, fmt.Errorf("%s: cannot write type %s to a header",
+				field.Name(), field.TypeString())
+		}
+
+		// Dates in headers are formatted according to HTTP.
+		if format.Format == FormatTime {
+			format.Format = FormatHttpTime
+		}
+
+		responseField.ResponseKind = ResponseHeader
+		responseField.Name = name
+		responseField.TextFormat = format
+		return responseField, true, nil
+	}
+
+	if _, ok := tag.Lookup(tCookie); ok {
+		cookie := field
+		if responseField.Slice = field.IsSlice(); responseField.Slice {
+			cookie = field.Elem()
+			responseField.Pointer = cookie.PointerDepth() > 0
+		}
+
+		if !isHttpType(cookie, "Cookie") || (responseField.Slice && field.PointerDepth() > 0) {
+			return responseField, false, fmt.Errorf("%s: cookie must be of type http.Cookie",
+				field.Name())
+		}
+
+		responseField.ResponseKind = ResponseCookie
+		return responseField, true, nil
+	}
+
+	return responseField, false, nil
+}
+
+func isHttpType(decl ParamDeclaration, typeName string) bool {
+	return decl.TypePackage() == "net/http" && decl.TypeName() == typeName
 }
 
 func isErrorParam(param ParamDeclaration) bool {
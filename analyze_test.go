@@ -0,0 +1,1080 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// errorCase is a package, that cannot be generated. The source is prefixed
+// with the package clause.
+type errorCase struct {
+	name   string
+	router string
+	src    string
+	want   string
+}
+
+var errorCases = []errorCase{
+	{
+		name: "unsupported conversion",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get binds a map.
+// Path: /
+// Query: m
+func (S) Get(m map[string]int) {}
+`,
+		want: "m: cannot convert string to unsupported type map[string]int",
+	},
+	{
+		name: "unsupported path conversion",
+		src: `
+type Point struct{ X, Y int }
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get binds a struct from the path.
+// Path: /{p}
+// Param: p=p
+func (S) Get(p Point) {}
+`,
+		want: "p: cannot convert string to unsupported type Point",
+	},
+	{
+		name: "slice of pointers",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get binds a slice of pointers.
+// Path: /
+// Query: ids
+func (S) Get(ids []*int) {}
+`,
+		want: "ids: slices of pointers are not supported",
+	},
+	{
+		name: "path default",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get declares a default for a path parameter.
+// Path: /{id}
+// Default: id=1
+func (S) Get(id int) {}
+`,
+		want: "id: path parameter id cannot have a default value",
+	},
+	{
+		name: "invalid default",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get declares a default, that is not an int.
+// Path: /
+// Query: limit
+// Default: limit=abc
+func (S) Get(limit int) {}
+`,
+		want: `limit: invalid default value "abc"`,
+	},
+	{
+		name: "unsupported request struct field",
+		src: `
+type Request struct {
+	M map[string]int ` + "`query:\"m\"`" + `
+}
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get binds a request struct.
+// Path: /
+func (S) Get(req Request) {}
+`,
+		want: "req: M: cannot convert string to unsupported type map[string]int",
+	},
+	{
+		name: "unsupported consumed media type",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Create consumes yaml.
+// Path: /
+// Method: POST
+// Consumes: application/yaml
+func (S) Create(p struct{ A int }) {}
+`,
+		want: `unsupported media type "application/yaml"`,
+	},
+	{
+		name: "slice from form",
+		src: `
+type Profile struct{ Name string }
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Create consumes a slice from a form.
+// Path: /
+// Method: POST
+// Consumes: application/x-www-form-urlencoded
+func (S) Create(p []Profile) {}
+`,
+		want: "p: cannot decode []Profile from application/x-www-form-urlencoded",
+	},
+	{
+		name: "consumes without payload",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Create consumes json, but has no payload.
+// Path: /
+// Method: POST
+// Consumes: application/json
+func (S) Create() {}
+`,
+		want: "consumed media types are declared, but there is no payload",
+	},
+	{
+		name: "unsupported produced media type",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get produces yaml.
+// Path: /
+// Produces: application/yaml
+func (S) Get() int { return 0 }
+`,
+		want: `unsupported media type "application/yaml"`,
+	},
+	{
+		name: "map as csv",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get produces a map as csv.
+// Path: /
+// Produces: text/csv
+func (S) Get() map[string]int { return nil }
+`,
+		want: "cannot encode map[string]int as text/csv",
+	},
+	{
+		name: "produces without response",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get produces json, but returns nothing.
+// Path: /
+// Produces: application/json
+func (S) Get() {}
+`,
+		want: "produced media types are declared, but there is no response body",
+	},
+	{
+		name: "invalid status code",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get declares an unknown status code.
+// Path: /
+// Status: 299
+func (S) Get() string { return "" }
+`,
+		want: `invalid status code "299"`,
+	},
+	{
+		name: "status without body",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get returns a body with 204 No Content.
+// Path: /
+// Status: 204
+func (S) Get() string { return "" }
+`,
+		want: "status code 204 does not allow a response body",
+	},
+	{
+		name: "response struct pointer",
+		src: `
+type Response struct {
+	Body string ` + "`body:\"\"`" + `
+}
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get returns a response struct.
+// Path: /
+func (S) Get() *Response { return nil }
+`,
+		want: "response struct *Response must be returned as a value",
+	},
+	{
+		name: "multiple bodies",
+		src: `
+type Response struct {
+	A string ` + "`body:\"\"`" + `
+	B string ` + "`body:\"\"`" + `
+}
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get returns a response struct.
+// Path: /
+func (S) Get() Response { return Response{} }
+`,
+		want: "Response: multiple fields are tagged as body",
+	},
+	{
+		name: "status field type",
+		src: `
+type Response struct {
+	Status string ` + "`status:\"\"`" + `
+}
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get returns a response struct.
+// Path: /
+func (S) Get() Response { return Response{} }
+`,
+		want: "Response: Status: status code must be of type int",
+	},
+	{
+		name: "header field type",
+		src: `
+type Response struct {
+	Tags map[string]string ` + "`header:\"X-Tags\"`" + `
+}
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get returns a response struct.
+// Path: /
+func (S) Get() Response { return Response{} }
+`,
+		want: "Response: Tags: cannot write type map[string]string to a header",
+	},
+	{
+		name: "cookie field type",
+		src: `
+type Response struct {
+	Session string ` + "`cookie:\"\"`" + `
+}
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get returns a response struct.
+// Path: /
+func (S) Get() Response { return Response{} }
+`,
+		want: "Response: Session: cookie must be of type http.Cookie",
+	},
+	{
+		name: "error mapping of a call result",
+		src: `
+func limit() int { return 1 }
+
+var Limit = limit()
+
+// S is a service.
+// Path: /s
+// Errors: Limit=404
+type S struct{}
+
+// Get returns an error.
+// Path: /
+func (S) Get() error { return nil }
+`,
+		want: "cannot map Limit, because it is not an error variable of the package or its imports",
+	},
+	{
+		name: "error mapping of a function",
+		src: `
+import "errors"
+
+var New = errors.New
+
+// S is a service.
+// Path: /s
+// Errors: New=404
+type S struct{}
+
+// Get returns an error.
+// Path: /
+func (S) Get() error { return nil }
+`,
+		want: "cannot map New, because it is not an error variable of the package or its imports",
+	},
+	{
+		name: "error mapping of an unknown variable",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get returns an error.
+// Path: /
+// Errors: ErrMissing=404
+func (S) Get() error { return nil }
+`,
+		want: "cannot map ErrMissing, because it is not an error variable of the package or its imports",
+	},
+	{
+		name: "error mapping to an invalid status code",
+		src: `
+import "errors"
+
+var ErrGone = errors.New("gone")
+
+// S is a service.
+// Path: /s
+// Errors: ErrGone=200
+type S struct{}
+
+// Get returns an error.
+// Path: /
+func (S) Get() error { return nil }
+`,
+		want: `cannot map ErrGone to invalid error status code "200"`,
+	},
+	{
+		name: "content type without stream",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get declares the content type of a string.
+// Path: /
+// Content-Type: text/plain
+func (S) Get() string { return "" }
+`,
+		want: "a content type can only be declared for streamed responses",
+	},
+	{
+		name: "invalid content type",
+		src: `
+import "io"
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get declares an invalid content type.
+// Path: /
+// Content-Type: text/plain; charset
+func (S) Get() io.Reader { return nil }
+`,
+		want: `invalid content type "text/plain; charset"`,
+	},
+	{
+		name: "produces of a stream",
+		src: `
+import "io"
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get declares the media type of a stream using Produces.
+// Path: /
+// Produces: text/plain
+func (S) Get() io.Reader { return nil }
+`,
+		want: "the media type of a stream must be declared as Content-Type",
+	},
+	{
+		name: "iter.Seq2",
+		src: `
+import "iter"
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get returns pairs.
+// Path: /
+func (S) Get() iter.Seq2[int, string] { return nil }
+`,
+		want: "generic type iter.Seq2[int, string] is not supported, only iter.Seq can be used",
+	},
+	{
+		name: "generic type",
+		src: `
+type Page[T any] struct{ Items []T }
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get returns a generic type.
+// Path: /
+func (S) Get() Page[int] { return Page[int]{} }
+`,
+		want: "generic type Page[int] is not supported, only iter.Seq can be used",
+	},
+	{
+		name: "send-only channel",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get returns a channel, that cannot be received from.
+// Path: /
+func (S) Get() chan<- int { return nil }
+`,
+		want: "cannot stream values of chan<- int",
+	},
+	{
+		name: "content type of events",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get declares the content type of events.
+// Path: /
+// Content-Type: application/json
+func (S) Get() <-chan int { return nil }
+`,
+		want: "the media type of the stream cannot be declared, it is always text/event-stream",
+	},
+	{
+		name: "ndjson of a string",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get streams a string.
+// Path: /
+// Stream: ndjson
+func (S) Get() string { return "" }
+`,
+		want: "only slices, channels and iterators can be streamed as ndjson",
+	},
+	{
+		name: "unsupported stream format",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get streams csv.
+// Path: /
+// Stream: csv
+func (S) Get() []int { return nil }
+`,
+		want: `unsupported stream format "csv"`,
+	},
+	{
+		name: "produces of ndjson",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get declares the media type of ndjson.
+// Path: /
+// Stream: ndjson
+// Produces: application/json
+func (S) Get() []int { return nil }
+`,
+		want: "the media type of the stream cannot be declared, it is always application/x-ndjson",
+	},
+	{
+		name: "invalid method token",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get declares a method with a space.
+// Path: /
+// Method: GET, NOT A TOKEN
+func (S) Get() {}
+`,
+		want: `invalid http method "NOT A TOKEN"`,
+	},
+	{
+		name: "any combined with other methods",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get declares ANY and POST.
+// Path: /
+// Method: ANY, POST
+func (S) Get() {}
+`,
+		want: "method ANY cannot be combined with other methods",
+	},
+	{
+		name: "duplicate methods",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get declares GET twice.
+// Path: /
+// Method: GET, get
+func (S) Get() {}
+`,
+		want: "method GET is declared more than once",
+	},
+	{
+		name: "unknown query binding",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get misspells its argument.
+// Path: /
+// Query: pge
+func (S) Get(page int) {}
+`,
+		want: `analyzing endpoint Get: Query: "pge" is not an argument of the endpoint`,
+	},
+	{
+		name: "unknown form binding",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Create binds a form value without an argument.
+// Path: /
+// Method: POST
+// Form: name
+func (S) Create() {}
+`,
+		want: `analyzing endpoint Create: Form: "name" is not an argument of the endpoint`,
+	},
+	{
+		name: "unknown header binding",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get binds a header to an unknown argument.
+// Path: /
+// Header: trace=X-Trace
+func (S) Get(traceID string) {}
+`,
+		want: `analyzing endpoint Get: Header: "trace" is not an argument of the endpoint`,
+	},
+	{
+		name: "unknown cookie binding",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get binds a cookie to an unknown argument.
+// Path: /
+// Cookie: sess=session
+func (S) Get(session string) {}
+`,
+		want: `analyzing endpoint Get: Cookie: "sess" is not an argument of the endpoint`,
+	},
+	{
+		name: "unknown param binding",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get maps a path parameter to an unknown argument.
+// Path: /{user-id}
+// Param: userID=user-id
+func (S) Get(id string) {}
+`,
+		want: `analyzing endpoint Get: Param: "userID" is not an argument of the endpoint`,
+	},
+	{
+		name: "unknown default binding",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get declares the default of an unknown argument.
+// Path: /
+// Query: limit
+// Default: limt=20
+func (S) Get(limit int) {}
+`,
+		want: `analyzing endpoint Get: Default: "limt" is not an argument of the endpoint`,
+	},
+	{
+		name: "missing path param",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get expects a path parameter, that is not declared.
+// Path: /
+func (S) Get(id string) {}
+`,
+		want: "argument id has no matching path parameter {id} in /s",
+	},
+	{
+		name: "unbound path param",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get ignores its path parameter.
+// Path: /{id}
+func (S) Get() {}
+`,
+		want: "path parameter {id} of /s/{id} is not bound to an argument",
+	},
+	{
+		name: "duplicate route",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// A is registered twice.
+// Path: /x
+func (S) A() {}
+
+// B is registered twice.
+// Path: /x
+func (S) B() {}
+`,
+		want: "duplicate route GET /s/x: S.B and S.A",
+	},
+	{
+		name: "shadowed route",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// A names its path parameter id.
+// Path: /{id}
+func (S) A(id string) {}
+
+// B names its path parameter name.
+// Path: /{name}
+func (S) B(name string) {}
+`,
+		want: "route GET /s/{id} of S.A is shadowed by /s/{name} of S.B",
+	},
+	{
+		name: "services at the same path",
+		src: `
+// A is a service.
+// Path: /s
+type A struct{}
+
+// Get is an endpoint of A.
+// Path: /a
+func (A) Get() {}
+
+// B is a service.
+// Path: /s
+type B struct{}
+
+// Get is an endpoint of B.
+// Path: /b
+func (B) Get() {}
+`,
+		want: "services B and A are registered at the same path /s",
+	},
+	{
+		name: "route shadowed by a service",
+		src: `
+// A is a service.
+// Path: /a
+type A struct{}
+
+// Get is below the path of B.
+// Path: /b/x
+func (A) Get() {}
+
+// B is a service.
+// Path: /a/b
+type B struct{}
+
+// Get is an endpoint of B.
+// Path: /y
+func (B) Get() {}
+`,
+		want: "route /a/b/x of A.Get is shadowed by the service B registered at /a/b",
+	},
+	{
+		name:   "httprouter wildcard conflict",
+		router: "httprouter",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get has a path parameter.
+// Path: /{id}
+func (S) Get(id string) {}
+
+// New has a static segment at the position of the path parameter.
+// Path: /new
+func (S) New() {}
+`,
+		want: `route /s/{id} of S.Get conflicts with route /s/new of S.New at "/s/{id}"`,
+	},
+	{
+		name:   "pattern on servemux",
+		router: "servemux",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get constrains its path parameter.
+// Path: /{id:[0-9]+}
+func (S) Get(id int) {}
+`,
+		want: "router servemux does not support the pattern of path parameter {id} in /s/{id:[0-9]+}",
+	},
+	{
+		name:   "pattern on httprouter",
+		router: "httprouter",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get constrains its path parameter.
+// Path: /{id:[0-9]+}
+func (S) Get(id int) {}
+`,
+		want: "router httprouter does not support the pattern of path parameter {id} in /s/{id:[0-9]+}",
+	},
+	{
+		name: "invalid pattern",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get constrains its path parameter with an invalid expression.
+// Path: /{id:[0-9}
+func (S) Get(id int) {}
+`,
+		want: "invalid pattern of path parameter {id}",
+	},
+	{
+		name: "catch-all before another segment",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get declares a segment after the catch-all.
+// Path: /{path...}/meta
+func (S) Get(path string) {}
+`,
+		want: "catch-all path parameter {path...} must be the last segment",
+	},
+	{
+		name: "converted catch-all",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get converts the catch-all.
+// Path: /{id...}
+func (S) Get(id int) {}
+`,
+		want: "catch-all path parameter {id} must be bound to a string argument",
+	},
+	{
+		name: "unmapped path parameter with a dash",
+		src: `
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Get does not map its path parameter.
+// Path: /{user-id}
+func (S) Get(userID int) {}
+`,
+		want: "argument userID has no matching path parameter {userID} in /s/{user-id}",
+	},
+	{
+		name: "mount at the path of the parent",
+		src: `
+// P is a service.
+// Path: /p
+type P struct {
+	// Mount: /
+	C *C
+}
+
+// Get is an endpoint of P.
+// Path: /x
+func (P) Get() {}
+
+// C has no path of its own.
+type C struct{}
+
+// Get is an endpoint of C.
+// Path: /y
+func (*C) Get() {}
+`,
+		want: "services P and P.C are registered at the same path /p",
+	},
+	{
+		name: "service mounted into itself",
+		src: `
+// S is a service.
+// Path: /s
+type S struct {
+	// Mount: /self
+	Self *S
+}
+
+// Get is an endpoint of S.
+// Path: /
+func (*S) Get() {}
+`,
+		want: "service S is mounted into itself",
+	},
+	{
+		name: "mounted field without ServeHTTP",
+		src: `
+// S is a service.
+// Path: /s
+type S struct {
+	// Mount: /static
+	Static int
+}
+
+// Get is an endpoint of S.
+// Path: /
+func (*S) Get() {}
+`,
+		want: "a mounted field must be a service or implement net/http.Handler",
+	},
+	{
+		name: "ServeHTTP with the request of another package",
+		src: `
+import (
+	"net/http"
+	"net/rpc"
+)
+
+// H declares ServeHTTP with a request of net/rpc.
+type H struct{}
+
+func (H) ServeHTTP(w http.ResponseWriter, r *rpc.Request) {}
+
+// S is a service.
+// Path: /s
+type S struct {
+	// Mount: /h
+	H H
+}
+
+// Get is an endpoint of S.
+// Path: /
+func (*S) Get() {}
+`,
+		want: "a mounted field must be a service or implement net/http.Handler",
+	},
+	{
+		name: "ServeHTTP with a local request",
+		src: `
+import "net/http"
+
+// Request is not the request of net/http.
+type Request struct{}
+
+// H declares ServeHTTP with a local request.
+type H struct{}
+
+func (H) ServeHTTP(w http.ResponseWriter, r *Request) {}
+
+// S is a service.
+// Path: /s
+type S struct {
+	// Mount: /h
+	H H
+}
+
+// Get is an endpoint of S.
+// Path: /
+func (*S) Get() {}
+`,
+		want: "a mounted field must be a service or implement net/http.Handler",
+	},
+	{
+		name: "mounted method with arguments",
+		src: `
+import "net/http"
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Files returns a handler of a folder.
+// Mount: /files
+func (*S) Files(dir string) http.Handler { return nil }
+
+// Get is an endpoint of S.
+// Path: /
+func (*S) Get() {}
+`,
+		want: "a mounted method must return a net/http.Handler without any arguments",
+	},
+	{
+		name: "stripped path with path parameters",
+		src: `
+import "net/http"
+
+// S is a service.
+// Path: /s
+type S struct {
+	// Mount: /{id}/files
+	Files http.Handler
+}
+
+// Get is an endpoint of S.
+// Path: /
+func (*S) Get() {}
+`,
+		want: "the path /s/{id}/files cannot be stripped, because it contains path parameters",
+	},
+	validationCase("unknown validation rule",
+		"Name string `validate:\"email\"`",
+		`Name: unknown validation rule "email"`),
+	validationCase("validation without argument",
+		"Name string `validate:\"min\"`",
+		`Name: validation rule "min" requires an argument`),
+	validationCase("validation bound overflow",
+		"Count int8 `validate:\"max=200\"`",
+		`Count: validation rule "max" requires an integer argument of type int8`),
+	validationCase("negative unsigned bound",
+		"Count uint `validate:\"min=-1\"`",
+		`Count: validation rule "min" requires a non-negative integer argument of type uint`),
+	validationCase("negative length",
+		"Tags []string `validate:\"max=-1\"`",
+		`Tags: validation rule "max" requires a non-negative integer argument`),
+	validationCase("infinite float bound",
+		"Ratio float64 `validate:\"max=Inf\"`",
+		`Ratio: validation rule "max" requires a finite numeric argument`),
+	validationCase("bound of unsupported type",
+		"Active bool `validate:\"min=1\"`",
+		`Active: validation rule "min" is not supported for type bool`),
+	validationCase("required struct",
+		"Inner struct{ A int } `validate:\"required\"`",
+		"Inner: cannot require a value of type struct"),
+}
+
+// validationCase is an error case of a payload with a single validated field.
+func validationCase(name, field, want string) errorCase {
+	return errorCase{
+		name: name,
+		src: `
+type Payload struct {
+		` + field + `
+}
+
+// S is a service.
+// Path: /s
+type S struct{}
+
+// Create validates the payload.
+// Path: /
+// Method: POST
+func (S) Create(p Payload) {}
+`,
+		want: want,
+	}
+}
+
+// TestGenerateErrors checks, that invalid packages are reported at generation
+// time instead of rendering code, that does not compile or misbehaves.
+func TestGenerateErrors(t *testing.T) {
+	for _, c := range errorCases {
+		t.Run(c.name, func(t *testing.T) {
+			router := c.router
+			if router == "" {
+				router = "chi"
+			}
+
+			defer renderCase{router: router}.apply()()
+
+			// The package must be inside the module to be imported.
+			dir, err := ioutil.TempDir("testdata", "invalid")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer os.RemoveAll(dir)
+
+			writeFile(t, filepath.Join(dir, "invalid.go"), []byte("package invalid\n"+c.src))
+
+			err = generate("./"+dir, filepath.Join(dir, "flowheater_gen.go"))
+			if err == nil {
+				t.Fatalf("generating succeeded, want error %q", c.want)
+			}
+
+			if !strings.Contains(err.Error(), c.want) {
+				t.Errorf("error = %q, want %q", err, c.want)
+			}
+		})
+	}
+}
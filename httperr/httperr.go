@@ -0,0 +1,187 @@
+// Package httperr contains the errors reported by routers generated by
+// flowheater. Endpoints return them to respond with a specific status code,
+// without depending on the generated code.
+package httperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrMissingParam is reported if a required parameter is absent.
+	ErrMissingParam = NewHttpError(http.StatusBadRequest, "missing parameter")
+
+	// ErrUnsupportedMediaType is reported if the payload cannot be decoded
+	// from the content type of the request.
+	ErrUnsupportedMediaType = NewHttpError(http.StatusUnsupportedMediaType, "unsupported media type")
+
+	// ErrNotAcceptable is reported if the response cannot be encoded to any
+	// media type accepted by the request.
+	ErrNotAcceptable = NewHttpError(http.StatusNotAcceptable, "not acceptable")
+
+	// ErrMethodNotAllowed is reported if a route does not accept the method
+	// of the request.
+	ErrMethodNotAllowed = NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+)
+
+// StatusError is implemented by errors, that are reported with a specific
+// status code.
+type StatusError interface {
+	error
+	StatusCode() int
+}
+
+// PublicError is implemented by errors, whose message is safe to be sent to
+// the client.
+type PublicError interface {
+	error
+	PublicMessage() string
+}
+
+// HttpError is an error with a status code and a message, that is sent to
+// the client. The optional cause is only logged.
+type HttpError struct {
+	Status  int
+	Message string
+	Err     error
+}
+
+// NewHttpError creates an error with a status code. The message defaults to
+// the text of the status code.
+func NewHttpError(status int, message string) *HttpError {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	return &HttpError{
+		Status:  status,
+		Message: message,
+	}
+}
+
+func (e *HttpError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+
+	return e.Message
+}
+
+func (e *HttpError) Unwrap() error {
+	return e.Err
+}
+
+func (e *HttpError) StatusCode() int {
+	return e.Status
+}
+
+func (e *HttpError) PublicMessage() string {
+	return e.Message
+}
+
+// BadRequest creates an error reported as 400 Bad Request.
+func BadRequest(message string) error {
+	return NewHttpError(http.StatusBadRequest, message)
+}
+
+// Unauthorized creates an error reported as 401 Unauthorized.
+func Unauthorized(message string) error {
+	return NewHttpError(http.StatusUnauthorized, message)
+}
+
+// Forbidden creates an error reported as 403 Forbidden.
+func Forbidden(message string) error {
+	return NewHttpError(http.StatusForbidden, message)
+}
+
+// NotFound creates an error reported as 404 Not Found.
+func NotFound(message string) error {
+	return NewHttpError(http.StatusNotFound, message)
+}
+
+// Conflict creates an error reported as 409 Conflict.
+func Conflict(message string) error {
+	return NewHttpError(http.StatusConflict, message)
+}
+
+// Gone creates an error reported as 410 Gone.
+func Gone(message string) error {
+	return NewHttpError(http.StatusGone, message)
+}
+
+// UnprocessableEntity creates an error reported as 422 Unprocessable Entity.
+func UnprocessableEntity(message string) error {
+	return NewHttpError(http.StatusUnprocessableEntity, message)
+}
+
+// TooManyRequests creates an error reported as 429 Too Many Requests.
+func TooManyRequests(message string) error {
+	return NewHttpError(http.StatusTooManyRequests, message)
+}
+
+// BindingError is returned if a parameter could not be bound to a value of
+// the request.
+type BindingError struct {
+	Source string
+	Name   string
+	Err    error
+}
+
+func (e *BindingError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%s: %v", e.Source, e.Err)
+	}
+
+	return fmt.Sprintf("%s parameter %s: %v", e.Source, e.Name, e.Err)
+}
+
+func (e *BindingError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode reports binding errors as bad requests.
+func (e *BindingError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// PublicMessage describes the binding error to the client. The cause is
+// only included, if it implements PublicError.
+func (e *BindingError) PublicMessage() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%s: %s", e.Source, e.Reason())
+	}
+
+	return fmt.Sprintf("%s parameter %s: %s", e.Source, e.Name, e.Reason())
+}
+
+// Reason returns the public message of the cause. Other causes, e.g. errors
+// of a decoder, may expose details of the implementation and are replaced by
+// "invalid value".
+func (e *BindingError) Reason() string {
+	var publicErr PublicError
+	if errors.As(e.Err, &publicErr) {
+		return publicErr.PublicMessage()
+	}
+
+	return "invalid value"
+}
+
+// Problem describes an error as defined by RFC 7807.
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam is an extension member of a problem, that describes a
+// parameter, that could not be bound.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	In     string `json:"in"`
+	Reason string `json:"reason"`
+}
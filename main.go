@@ -11,6 +11,8 @@ var (
 	customErrorHandler   bool
 	customRequestReader  bool
 	customResponseWriter bool
+	problemDetails       bool
+	routerName           string
 )
 
 func init() {
@@ -36,10 +38,24 @@ func init() {
 		false,
 		"Enable custom response writer as a parameter on the router")
 
-	flag.Parse()
+	flag.BoolVar(&problemDetails,
+		"problem-details",
+		false,
+		"Respond to errors with application/problem+json documents")
+
+	flag.StringVar(&routerName,
+		"router",
+		"chi",
+		"Router to register the endpoints with (chi, servemux, gorilla, httprouter)")
 }
 
 func main() {
+	flag.Parse()
+
+	if _, ok := routerBackends[routerName]; !ok {
+		log.Fatalf("ERROR: unsupported router %q", routerName)
+	}
+
 	// Step 1: Parse the source package and search for annotated services.
 	sourcePackage, err := ParsePackage(packageFolder)
 	if err != nil {
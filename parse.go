@@ -1,17 +1,56 @@
 package main
 
 import (
+	"fmt"
+	"go/ast"
 	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
 	"log"
+	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/wzshiming/gotype"
 )
 
 const (
-	aPath    = "path"
-	aMethod  = "method"
-	mResolve = "resolveParam"
+	aPath        = "path"
+	aMethod      = "method"
+	aQuery       = "query"
+	aHeader      = "header"
+	aCookie      = "cookie"
+	aDefault     = "default"
+	aForm        = "form"
+	aConsumes    = "consumes"
+	aProduces    = "produces"
+	aStatus      = "status"
+	aErrors      = "errors"
+	aContentType = "content-type"
+	aStream      = "stream"
+	aParam       = "param"
+	aMount       = "mount"
+	aStripPrefix = "strip-prefix"
+	mResolve     = "resolveParam"
+
+	mUnmarshalText = "UnmarshalText"
+	mValidate      = "Validate"
+	mString        = "String"
+	mError         = "Error"
+	mServeHTTP     = "ServeHTTP"
+	mIsZero        = "IsZero"
+
+	tPath     = "path"
+	tQuery    = "query"
+	tHeader   = "header"
+	tCookie   = "cookie"
+	tForm     = "form"
+	tDefault  = "default"
+	tBody     = "body"
+	tStatus   = "status"
+	tValidate = "validate"
+	tJson     = "json"
 )
 
 // Annotations is a map of key-value pairs.
@@ -58,6 +97,50 @@ func (a Annotations) Exists(key string) bool {
 	return ok
 }
 
+// List returns the value associated with a given key as a comma separated
+// list. Empty items are omitted.
+func (a Annotations) List(key string) []string {
+	var items []string
+
+	for _, item := range strings.Split(a.Get(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// AnnotationPair is a single item of an annotation in the format
+// "<Name>=<Value>".
+type AnnotationPair struct {
+	Name  string
+	Value string
+}
+
+// Pairs returns the value associated with a given key as a comma separated
+// list of pairs. Items without an explicit value use their name as value.
+func (a Annotations) Pairs(key string) []AnnotationPair {
+	var pairs []AnnotationPair
+
+	for _, item := range a.List(key) {
+		var (
+			parts = strings.SplitN(item, "=", 2)
+			pair  = AnnotationPair{Name: strings.TrimSpace(parts[0])}
+		)
+
+		if len(parts) > 1 {
+			pair.Value = strings.TrimSpace(parts[1])
+		} else {
+			pair.Value = pair.Name
+		}
+
+		pairs = append(pairs, pair)
+	}
+
+	return pairs
+}
+
 // SourcePackage is a collection of annotated services and their endpoints
 // found in a user provided go source package.
 type SourcePackage struct {
@@ -79,7 +162,7 @@ func ParsePackage(packageName string) (*SourcePackage, error) {
 
 	log.Printf("Parsing package %s", info.Dir)
 
-	node, err := importer.Import(packageName, "")
+	node, err := importPackage(importer, info)
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +175,427 @@ func ParsePackage(packageName string) (*SourcePackage, error) {
 	}, nil
 }
 
+// importPackage parses the source files of a package. The importer does not
+// understand generics, so instantiations of iter.Seq are replaced by their
+// underlying function type and all other generic types are rejected.
+func importPackage(importer *gotype.Importer, info *build.Package) (gotype.Type, error) {
+	pkg := &ast.Package{
+		Name:  info.Name,
+		Files: make(map[string]*ast.File),
+	}
+
+	for _, name := range info.GoFiles {
+		filename := filepath.Join(info.Dir, name)
+
+		file, err := parser.ParseFile(importer.FileSet(), filename, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := replaceIterators(importer.FileSet(), file); err != nil {
+			return nil, err
+		}
+
+		pkg.Files[filename] = file
+	}
+
+	seeder := stdlibSeeder{importer: importer, visited: make(map[string]bool)}
+	for _, path := range info.Imports {
+		if err := seeder.seed(path, info.Dir); err != nil {
+			return nil, err
+		}
+	}
+
+	return importer.ImportPackage(info.ImportPath, pkg)
+}
+
+// replaceIterators replaces "iter.Seq[T]" by "func(yield func(T) bool)" in
+// all declared types of a file.
+func replaceIterators(fset *token.FileSet, file *ast.File) error {
+	r := iteratorReplacer{fset: fset}
+
+	for _, spec := range file.Imports {
+		if spec.Path.Value == `"iter"` {
+			r.iterName = "iter"
+			if spec.Name != nil {
+				r.iterName = spec.Name.Name
+			}
+		}
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv != nil {
+				if err := r.replaceFields(decl.Recv); err != nil {
+					return err
+				}
+			}
+
+			if err := r.replaceFuncType(decl.Type); err != nil {
+				return err
+			}
+
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				var err error
+
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					spec.Type, err = r.replace(spec.Type)
+				case *ast.ValueSpec:
+					if spec.Type != nil {
+						spec.Type, err = r.replace(spec.Type)
+					}
+				}
+
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+type iteratorReplacer struct {
+	fset     *token.FileSet
+	iterName string
+}
+
+func (r *iteratorReplacer) replace(expr ast.Expr) (ast.Expr, error) {
+	var err error
+
+	switch t := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		return expr, nil
+
+	case *ast.StarExpr:
+		t.X, err = r.replace(t.X)
+	case *ast.ParenExpr:
+		t.X, err = r.replace(t.X)
+	case *ast.Ellipsis:
+		t.Elt, err = r.replace(t.Elt)
+	case *ast.ArrayType:
+		t.Elt, err = r.replace(t.Elt)
+	case *ast.ChanType:
+		t.Value, err = r.replace(t.Value)
+
+	case *ast.MapType:
+		if t.Key, err = r.replace(t.Key); err == nil {
+			t.Value, err = r.replace(t.Value)
+		}
+
+	case *ast.FuncType:
+		err = r.replaceFuncType(t)
+	case *ast.StructType:
+		err = r.replaceFields(t.Fields)
+	case *ast.InterfaceType:
+		err = r.replaceFields(t.Methods)
+
+	case *ast.IndexExpr:
+		if !r.isSeq(t.X) {
+			return nil, r.unsupported(expr)
+		}
+
+		elem, err := r.replace(t.Index)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.FuncType{
+			Params: &ast.FieldList{List: []*ast.Field{{
+				Names: []*ast.Ident{ast.NewIdent("yield")},
+				Type: &ast.FuncType{
+					Params:  &ast.FieldList{List: []*ast.Field{{Type: elem}}},
+					Results: &ast.FieldList{List: []*ast.Field{{Type: ast.NewIdent("bool")}}},
+				},
+			}}},
+		}, nil
+
+	default:
+		// Instantiations with multiple type arguments, e.g. iter.Seq2[K, V].
+		return nil, r.unsupported(expr)
+	}
+
+	return expr, err
+}
+
+func (r *iteratorReplacer) replaceFuncType(t *ast.FuncType) error {
+	if err := r.replaceFields(t.Params); err != nil {
+		return err
+	}
+
+	return r.replaceFields(t.Results)
+}
+
+func (r *iteratorReplacer) replaceFields(fields *ast.FieldList) error {
+	if fields == nil {
+		return nil
+	}
+
+	for _, field := range fields.List {
+		var err error
+		if field.Type, err = r.replace(field.Type); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *iteratorReplacer) isSeq(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Seq" {
+		return false
+	}
+
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && r.iterName != "" && pkg.Name == r.iterName
+}
+
+func (r *iteratorReplacer) unsupported(expr ast.Expr) error {
+	return fmt.Errorf("%s: generic type %s is not supported, only iter.Seq can be used",
+		r.fset.Position(expr.Pos()), types.ExprString(expr))
+}
+
+// stdlibSeeder imports the packages of the standard library, that a package
+// depends on, before the package itself. The importer does not understand
+// generics and panics on the generic code of the standard library, e.g. in
+// sync/atomic. So only the declarations of those packages are imported,
+// stripped of their type parameters and function bodies.
+type stdlibSeeder struct {
+	importer *gotype.Importer
+	visited  map[string]bool
+}
+
+// seed imports a package of the standard library after its dependencies.
+// Other packages are not imported, but searched for dependencies on the
+// standard library.
+func (s *stdlibSeeder) seed(path, srcDir string) error {
+	if path == "C" || path == "unsafe" || s.visited[path] {
+		return nil
+	}
+
+	s.visited[path] = true
+
+	info, err := build.Import(path, srcDir, 0)
+	if err != nil {
+		// Unresolvable imports are reported by the importer.
+		return nil
+	}
+
+	if !info.Goroot {
+		for _, imported := range info.Imports {
+			if err := s.seed(imported, info.Dir); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	pkg := &ast.Package{
+		Name:  info.Name,
+		Files: make(map[string]*ast.File),
+	}
+
+	for _, name := range info.GoFiles {
+		filename := filepath.Join(info.Dir, name)
+
+		file, err := parser.ParseFile(s.importer.FileSet(), filename, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+
+		stripGenerics(file)
+
+		for _, imported := range usedImports(file) {
+			if err := s.seed(imported, info.Dir); err != nil {
+				return err
+			}
+		}
+
+		pkg.Files[filename] = file
+	}
+
+	// The importer looks up imported packages by their directory.
+	_, err = s.importer.ImportPackage(info.Dir, pkg)
+	return err
+}
+
+// usedImports returns the import paths of a file, that are referenced by a
+// qualified identifier. Imports only used by stripped function bodies are
+// not needed to import the file.
+func usedImports(file *ast.File) []string {
+	names := make(map[string]string)
+
+	for _, spec := range file.Imports {
+		path := strings.Trim(spec.Path.Value, `"`)
+
+		name := filepath.Base(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		names[name] = path
+	}
+
+	var paths []string
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				if path, ok := names[ident.Name]; ok {
+					paths = append(paths, path)
+					delete(names, ident.Name)
+				}
+			}
+		}
+
+		return true
+	})
+
+	return paths
+}
+
+// stripGenerics removes function bodies and type parameters from a file and
+// replaces instantiated generic types by their origin. Variables, whose type
+// cannot be determined without the removed code, are dropped.
+func stripGenerics(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			decl.Body = nil
+			stripFields(decl.Recv)
+			stripFuncType(decl.Type)
+
+		case *ast.GenDecl:
+			var specs []ast.Spec
+
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					spec.TypeParams = nil
+					spec.Type = stripType(spec.Type)
+
+				case *ast.ValueSpec:
+					if decl.Tok == token.VAR && !stripVar(spec) {
+						continue
+					}
+				}
+
+				specs = append(specs, spec)
+			}
+
+			decl.Specs = specs
+		}
+	}
+}
+
+// stripVar keeps the declared type of a variable or an initializer, that
+// is a call of a function. Other initializers are not supported.
+func stripVar(spec *ast.ValueSpec) bool {
+	if spec.Type != nil {
+		spec.Type = stripType(spec.Type)
+		spec.Values = nil
+		return true
+	}
+
+	for _, value := range spec.Values {
+		call, ok := value.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+
+		switch fun := call.Fun.(type) {
+		case *ast.Ident, *ast.SelectorExpr:
+		case *ast.IndexExpr:
+			call.Fun = fun.X
+		case *ast.IndexListExpr:
+			call.Fun = fun.X
+		default:
+			return false
+		}
+
+		ast.Inspect(call, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.CompositeLit:
+				node.Type = stripType(node.Type)
+			case *ast.FuncLit:
+				node.Body = &ast.BlockStmt{}
+				stripFuncType(node.Type)
+				return false
+			}
+
+			return true
+		})
+	}
+
+	return true
+}
+
+func stripFuncType(t *ast.FuncType) {
+	t.TypeParams = nil
+	stripFields(t.Params)
+	stripFields(t.Results)
+}
+
+func stripFields(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+
+	var list []*ast.Field
+
+	for _, field := range fields.List {
+		switch field.Type.(type) {
+		case *ast.BinaryExpr, *ast.UnaryExpr:
+			// Type sets of constraints, e.g. ~int | ~uint.
+			continue
+		}
+
+		field.Type = stripType(field.Type)
+		list = append(list, field)
+	}
+
+	fields.List = list
+}
+
+func stripType(expr ast.Expr) ast.Expr {
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		return stripType(t.X)
+	case *ast.IndexListExpr:
+		return stripType(t.X)
+
+	case *ast.StarExpr:
+		t.X = stripType(t.X)
+	case *ast.ParenExpr:
+		t.X = stripType(t.X)
+	case *ast.Ellipsis:
+		t.Elt = stripType(t.Elt)
+	case *ast.ArrayType:
+		t.Elt = stripType(t.Elt)
+	case *ast.ChanType:
+		t.Value = stripType(t.Value)
+
+	case *ast.MapType:
+		t.Key = stripType(t.Key)
+		t.Value = stripType(t.Value)
+
+	case *ast.FuncType:
+		stripFuncType(t)
+	case *ast.StructType:
+		stripFields(t.Fields)
+	case *ast.InterfaceType:
+		stripFields(t.Methods)
+	}
+
+	return expr
+}
+
 func (s *SourcePackage) Filepath() string {
 	return s.info.Dir
 }
@@ -101,11 +605,110 @@ func (s *SourcePackage) Name() string {
 	return s.node.Name()
 }
 
+// Path returns the import path of the package.
+func (s *SourcePackage) Path() string {
+	return s.node.PkgPath()
+}
+
 // Services returns a slice of declared services.
 func (s *SourcePackage) Services() []ServiceDeclaration {
 	return s.services
 }
 
+// LookupErrorVar looks up a package-level variable, that holds an error. Variables
+// of imported packages are referenced by their qualified name, e.g.
+// "store.ErrNotFound", and returned along with the import path of their
+// package.
+func (s *SourcePackage) LookupErrorVar(name string) (pkgPath, varName string, ok bool) {
+	scope, varName := s.node, name
+
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		imported, ok := s.node.ChildByName(name[:i])
+		if !ok || imported.Kind() != gotype.Scope {
+			return "", "", false
+		}
+
+		scope, pkgPath, varName = imported, imported.PkgPath(), name[i+1:]
+		if !ast.IsExported(varName) {
+			return "", "", false
+		}
+	}
+
+	node, ok := scope.ChildByName(varName)
+	if !ok || node.Kind() != gotype.Declaration {
+		return "", "", false
+	}
+
+	return pkgPath, varName, isErrorDeclaration(ParamDeclaration{node: node})
+}
+
+// isErrorDeclaration tests whether the type of a variable is error or
+// implements it. Variables initialized by a call, e.g. to errors.New, have the
+// type of the result of the called function.
+func isErrorDeclaration(decl ParamDeclaration) bool {
+	var (
+		node   = decl.node
+		result = -1
+	)
+
+	// Variables initialized by another variable or by a call are declared
+	// as the initializer.
+	for node.Kind() == gotype.Declaration {
+		switch origin := node.Origin().(type) {
+		case *ast.ValueSpec:
+			result = callResult(origin, node.Name())
+		case *ast.CallExpr:
+			if result < 0 {
+				result = 0
+			}
+		}
+
+		node = node.Declaration()
+	}
+
+	// Calls of functions declared after the variable are not evaluated and
+	// yield the function instead of its result.
+	if node.Kind() == gotype.Func {
+		if result < 0 || result >= node.NumOut() {
+			return false
+		}
+
+		return isErrorDeclaration(ParamDeclaration{node: node.Out(result)})
+	}
+
+	typed := ParamDeclaration{node: decl.node, typ: node}
+	if typed.IsBuiltIn() {
+		return typed.TypeName() == "error"
+	}
+
+	_, ok := typed.derefType().MethodByName(mError)
+	return ok
+}
+
+// callResult returns the index of the result of a call, that initializes a
+// variable, or -1 if the variable is not initialized by a call.
+func callResult(spec *ast.ValueSpec, name string) int {
+	for i, ident := range spec.Names {
+		if ident.Name != name {
+			continue
+		}
+
+		switch {
+		case len(spec.Values) == len(spec.Names):
+			if _, ok := ast.Unparen(spec.Values[i]).(*ast.CallExpr); ok {
+				return 0
+			}
+
+		case len(spec.Values) == 1:
+			if _, ok := ast.Unparen(spec.Values[0]).(*ast.CallExpr); ok {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
 func findServiceDeclarations(pkgNode gotype.Type) []ServiceDeclaration {
 	var services []ServiceDeclaration
 
@@ -127,10 +730,15 @@ func findServiceDeclarations(pkgNode gotype.Type) []ServiceDeclaration {
 }
 
 func findEndpointDeclarations(serviceNode gotype.Type) []EndpointDeclaration {
-	var endpoints []EndpointDeclaration
+	var (
+		endpoints []EndpointDeclaration
+		methods   = make(map[string]bool)
+	)
 
 	for i, length := 0, serviceNode.NumMethod(); i < length; i++ {
 		node := serviceNode.Method(i)
+		methods[node.Name()] = true
+
 		if a := parseAnnotations(node); a.Exists(aPath) {
 			log.Printf("\t\t=> Found endpoint declaration: %s.%s",
 				serviceNode, node)
@@ -141,9 +749,39 @@ func findEndpointDeclarations(serviceNode gotype.Type) []EndpointDeclaration {
 		}
 	}
 
+	// Annotated methods of embedded structs are promoted, unless the struct
+	// declares a method of the same name itself.
+	for i, length := 0, serviceNode.NumField(); i < length; i++ {
+		field := serviceNode.Field(i)
+		if !field.IsAnonymous() || parseAnnotations(field).Exists(aMount) {
+			continue
+		}
+
+		embedded, ok := structType(field.Elem())
+		if !ok {
+			continue
+		}
+
+		for _, endpoint := range findEndpointDeclarations(embedded) {
+			if !methods[endpoint.Name()] {
+				methods[endpoint.Name()] = true
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+
 	return endpoints
 }
 
+// structType dereferences pointers to a struct type.
+func structType(node gotype.Type) (gotype.Type, bool) {
+	for node.Kind() == gotype.Ptr {
+		node = node.Elem()
+	}
+
+	return node, node.Kind() == gotype.Struct
+}
+
 func findResolverDeclarations(pkgNode gotype.Type) []ResolverDeclaration {
 	var resolvers []ResolverDeclaration
 
@@ -162,11 +800,13 @@ func findResolverDeclarations(pkgNode gotype.Type) []ResolverDeclaration {
 }
 
 // ServiceDeclaration captures the information of a struct type, that is
-// annotated with at least "Path: <path-value>".
+// annotated with at least "Path: <path-value>", or mounted into another
+// service by a field annotated with "Mount: <path-value>".
 type ServiceDeclaration struct {
 	node        gotype.Type
 	annotations Annotations
 	endpoints   []EndpointDeclaration
+	field       gotype.Type // Field mounting the service, if any
 }
 
 func (s *ServiceDeclaration) Name() string {
@@ -185,6 +825,187 @@ func (s *ServiceDeclaration) Endpoints() []EndpointDeclaration {
 	return s.endpoints
 }
 
+// FieldName returns the name of the field mounting the service into its
+// parent.
+func (s *ServiceDeclaration) FieldName() string {
+	return s.field.Name()
+}
+
+// Mount returns the path of a mounted service relative to its parent.
+func (s *ServiceDeclaration) Mount() string {
+	return parseAnnotations(s.field).Get(aMount)
+}
+
+// Services returns the services mounted by fields of a struct type, which are
+// annotated with "Mount: <path-value>".
+func (s *ServiceDeclaration) Services() []ServiceDeclaration {
+	var services []ServiceDeclaration
+
+	for i, length := 0, s.node.NumField(); i < length; i++ {
+		field := s.node.Field(i)
+		if !parseAnnotations(field).Exists(aMount) {
+			continue
+		}
+
+		if !isServiceType(field.Elem()) {
+			continue
+		}
+
+		node, _ := structType(field.Elem())
+
+		services = append(services, ServiceDeclaration{
+			node:        node,
+			annotations: parseAnnotations(node),
+			endpoints:   findEndpointDeclarations(node),
+			field:       field,
+		})
+	}
+
+	return services
+}
+
+// Handlers returns the fields and methods of a struct type providing a
+// net/http.Handler, which are annotated with "Mount: <path-value>".
+func (s *ServiceDeclaration) Handlers() []HandlerDeclaration {
+	var handlers []HandlerDeclaration
+
+	for i, length := 0, s.node.NumField(); i < length; i++ {
+		field := s.node.Field(i)
+		if a := parseAnnotations(field); a.Exists(aMount) && !isServiceType(field.Elem()) {
+			log.Printf("\t\t=> Found handler declaration: %s.%s", s.node, field.Name())
+			handlers = append(handlers, HandlerDeclaration{
+				node:        field,
+				annotations: a,
+			})
+		}
+	}
+
+	for i, length := 0, s.node.NumMethod(); i < length; i++ {
+		node := s.node.Method(i)
+		if a := parseAnnotations(node); a.Exists(aMount) {
+			log.Printf("\t\t=> Found handler declaration: %s.%s", s.node, node)
+			handlers = append(handlers, HandlerDeclaration{
+				node:        node,
+				annotations: a,
+				method:      true,
+			})
+		}
+	}
+
+	return handlers
+}
+
+// isHandlerType tests whether a type implements net/http.Handler, i.e.
+// declares ServeHTTP(http.ResponseWriter, *http.Request) with the types of
+// net/http.
+func isHandlerType(node gotype.Type) bool {
+	for node.Kind() == gotype.Ptr {
+		node = node.Elem()
+	}
+
+	method, ok := node.MethodByName(mServeHTTP)
+	if !ok {
+		return false
+	}
+
+	fn := method.Declaration()
+	if fn.Kind() != gotype.Func || fn.NumIn() != 2 || fn.NumOut() != 0 {
+		return false
+	}
+
+	return isDeclaredAs(fn.In(0), "ResponseWriter", false) &&
+		isDeclaredAs(fn.In(1), "Request", true)
+}
+
+// isDeclaredAs tests whether the type of a parameter is "net/http.<name>" or,
+// if pointer is set, "*net/http.<name>". Types of other packages with the
+// same name do not match.
+func isDeclaredAs(param gotype.Type, name string, pointer bool) bool {
+	node := param.Declaration()
+
+	if pointer {
+		if node.Kind() != gotype.Ptr {
+			return false
+		}
+
+		node = node.Elem()
+	}
+
+	return node.Kind() != gotype.Ptr &&
+		node.Name() == name &&
+		importPath(node.PkgPath()) == "net/http"
+}
+
+// isServiceType tests whether a mounted field is a service, i.e. a struct
+// without a ServeHTTP method. Structs declaring the method with another
+// signature are reported as handlers, that do not implement net/http.Handler.
+func isServiceType(node gotype.Type) bool {
+	node, ok := structType(node)
+	if !ok {
+		return false
+	}
+
+	_, ok = node.MethodByName(mServeHTTP)
+	return !ok
+}
+
+// HandlerDeclaration captures the information of a field or method providing
+// a net/http.Handler, that is annotated with "Mount: <path-value>".
+type HandlerDeclaration struct {
+	node        gotype.Type
+	annotations Annotations
+	method      bool
+}
+
+func (h *HandlerDeclaration) Name() string {
+	return h.node.Name()
+}
+
+func (h *HandlerDeclaration) Annotations() Annotations {
+	return h.annotations
+}
+
+func (h *HandlerDeclaration) Mount() string {
+	return h.Annotations().Get(aMount)
+}
+
+// IsMethod tests whether the handler is returned by a method instead of being
+// the value of a field.
+func (h *HandlerDeclaration) IsMethod() bool {
+	return h.method
+}
+
+// IsHandler tests whether the type of a field implements net/http.Handler.
+func (h *HandlerDeclaration) IsHandler() bool {
+	return isHandlerType(h.node.Elem())
+}
+
+func (h *HandlerDeclaration) InputParams() []ParamDeclaration {
+	var (
+		fn     = h.node.Declaration()
+		params []ParamDeclaration
+	)
+
+	for i, length := 0, fn.NumIn(); i < length; i++ {
+		params = append(params, ParamDeclaration{node: fn.In(i)})
+	}
+
+	return params
+}
+
+func (h *HandlerDeclaration) OutputParams() []ParamDeclaration {
+	var (
+		fn     = h.node.Declaration()
+		params []ParamDeclaration
+	)
+
+	for i, length := 0, fn.NumOut(); i < length; i++ {
+		params = append(params, ParamDeclaration{node: fn.Out(i)})
+	}
+
+	return params
+}
+
 // EndpointDeclaration captures the information of a method, that is annotated
 // annotated with at least "Path: <path-value>".
 type EndpointDeclaration struct {
@@ -272,16 +1093,25 @@ func (r *ResolverDeclaration) OutputParams() []ParamDeclaration {
 // ParamDeclaration captures the information for method in- and output params.
 type ParamDeclaration struct {
 	node gotype.Type
+	typ  gotype.Type // Overrides the declared type, e.g. for slice elements
 }
 
 func (p *ParamDeclaration) Name() string {
 	return p.node.Name()
 }
 
+func (p *ParamDeclaration) declaration() gotype.Type {
+	if p.typ != nil {
+		return p.typ
+	}
+
+	return p.node.Declaration()
+}
+
 func (p *ParamDeclaration) deref() (int, gotype.Type) {
 	var (
 		depth = 0
-		node  = p.node.Declaration()
+		node  = p.declaration()
 	)
 
 	for node.Kind() == gotype.Ptr {
@@ -297,8 +1127,20 @@ func (p *ParamDeclaration) derefType() gotype.Type {
 	return t
 }
 
+// TypePackage returns the import path of the type.
 func (p *ParamDeclaration) TypePackage() string {
-	return p.derefType().PkgPath()
+	return importPath(p.derefType().PkgPath())
+}
+
+// importPath trims the package path of a type to its import path. Packages
+// of the standard library are imported by their directory.
+func importPath(pkgPath string) string {
+	if rel, err := filepath.Rel(filepath.Join(build.Default.GOROOT, "src"), pkgPath); err == nil &&
+		filepath.IsAbs(pkgPath) && !strings.HasPrefix(rel, "..") {
+		return strings.TrimPrefix(filepath.ToSlash(rel), "vendor/")
+	}
+
+	return pkgPath
 }
 
 func (p *ParamDeclaration) TypeName() string {
@@ -309,8 +1151,164 @@ func (p *ParamDeclaration) IsBuiltIn() bool {
 	return gotype.IsBuiltin(p.derefType())
 }
 
+// IsSlice tests whether the type is a slice.
+func (p *ParamDeclaration) IsSlice() bool {
+	return p.derefType().Kind() == gotype.Slice
+}
+
+// Elem returns the declaration of the elements of a slice param. The name of
+// the param is retained.
+func (p *ParamDeclaration) Elem() ParamDeclaration {
+	return ParamDeclaration{
+		node: p.node,
+		typ:  p.derefType().Elem(),
+	}
+}
+
+// IsStruct tests whether the type is a struct.
+func (p *ParamDeclaration) IsStruct() bool {
+	return p.derefType().Kind() == gotype.Struct
+}
+
+// Fields returns the declarations of the fields of a struct param.
+func (p *ParamDeclaration) Fields() []ParamDeclaration {
+	var (
+		t      = p.derefType()
+		fields []ParamDeclaration
+	)
+
+	for i, length := 0, t.NumField(); i < length; i++ {
+		field := t.Field(i)
+		fields = append(fields, ParamDeclaration{
+			node: field,
+			typ:  field.Elem(),
+		})
+	}
+
+	return fields
+}
+
+// Tag returns the tag of a struct field declaration.
+func (p *ParamDeclaration) Tag() reflect.StructTag {
+	if p.node.Kind() != gotype.Field {
+		return ""
+	}
+
+	return p.node.Tag()
+}
+
+// IsBytes tests whether the type is a slice of bytes.
+func (p *ParamDeclaration) IsBytes() bool {
+	t := p.derefType()
+	if t.Kind() != gotype.Slice {
+		return false
+	}
+
+	switch t.Elem().Kind() {
+	case gotype.Byte, gotype.Uint8:
+		return true
+	}
+
+	return false
+}
+
+// IsTextUnmarshaler tests whether the type implements
+// encoding.TextUnmarshaler.
+func (p *ParamDeclaration) IsTextUnmarshaler() bool {
+	_, ok := p.derefType().MethodByName(mUnmarshalText)
+	return ok
+}
+
+// IsValidator tests whether the type has a method "Validate() error".
+func (p *ParamDeclaration) IsValidator() bool {
+	fn, ok := p.derefType().MethodByName(mValidate)
+	if !ok {
+		return false
+	}
+
+	if fn = fn.Declaration(); fn.NumIn() != 0 || fn.NumOut() != 1 {
+		return false
+	}
+
+	out := ParamDeclaration{node: fn.Out(0)}
+	return out.IsBuiltIn() && out.TypeName() == "error"
+}
+
+// IsStringer tests whether the type has a method "String() string".
+func (p *ParamDeclaration) IsStringer() bool {
+	fn, ok := p.derefType().MethodByName(mString)
+	if !ok {
+		return false
+	}
+
+	if fn = fn.Declaration(); fn.NumIn() != 0 || fn.NumOut() != 1 {
+		return false
+	}
+
+	out := ParamDeclaration{node: fn.Out(0)}
+	return out.IsBuiltIn() && out.TypeName() == "string"
+}
+
+// IsZeroer tests whether the type has an "IsZero() bool" method, e.g.
+// time.Time.
+func (p *ParamDeclaration) IsZeroer() bool {
+	fn, ok := p.derefType().MethodByName(mIsZero)
+	if !ok {
+		return false
+	}
+
+	if fn = fn.Declaration(); fn.NumIn() != 0 || fn.NumOut() != 1 {
+		return false
+	}
+
+	out := ParamDeclaration{node: fn.Out(0)}
+	return out.IsBuiltIn() && out.TypeName() == "bool"
+}
+
+// IsHandler tests whether the type implements net/http.Handler.
+func (p *ParamDeclaration) IsHandler() bool {
+	return isHandlerType(p.declaration())
+}
+
+// IsReceiveChan tests whether the type is a channel, that values can be
+// received from.
+func (p *ParamDeclaration) IsReceiveChan() bool {
+	t := p.derefType()
+
+	// The directions of gotype are named after the position of the arrow,
+	// e.g. "<-chan" is a SendDir.
+	return t.Kind() == gotype.Chan && t.ChanDir() != gotype.RecvDir
+}
+
+// IsIterator tests whether the type is a function iterating over values,
+// i.e. "func(yield func(T) bool)".
+func (p *ParamDeclaration) IsIterator() bool {
+	t := p.derefType()
+	if t.Kind() != gotype.Func || t.NumIn() != 1 || t.NumOut() != 0 {
+		return false
+	}
+
+	yield := t.In(0).Declaration()
+	if yield.Kind() != gotype.Func || yield.NumIn() != 1 || yield.NumOut() != 1 {
+		return false
+	}
+
+	out := ParamDeclaration{node: yield.Out(0)}
+	return out.IsBuiltIn() && out.TypeName() == "bool"
+}
+
+// Kind returns the kind of the type.
+func (p *ParamDeclaration) Kind() gotype.Kind {
+	return p.derefType().Kind()
+}
+
 func (p *ParamDeclaration) IsLocal() bool {
-	return !strings.ContainsRune(p.node.Declaration().String(), '.')
+	return !strings.ContainsRune(p.TypeString(), '.')
+}
+
+// TypeString returns the type of the param as declared.
+func (p *ParamDeclaration) TypeString() string {
+	return p.declaration().String()
 }
 
 func (p *ParamDeclaration) PointerDepth() int {
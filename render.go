@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/dave/jennifer/jen"
@@ -9,9 +11,20 @@ import (
 const (
 	pkgHttp    = "net/http"
 	pkgChi     = "github.com/go-chi/chi"
+	pkgMux     = "github.com/gorilla/mux"
+	pkgRouter  = "github.com/julienschmidt/httprouter"
 	pkgStrconv = "strconv"
 	pkgJson    = "encoding/json"
 	pkgLog     = "log"
+	pkgErrors  = "errors"
+	pkgFmt     = "fmt"
+	pkgTime    = "time"
+	pkgStrings = "strings"
+	pkgXml     = "encoding/xml"
+	pkgMime    = "mime"
+	pkgCsv     = "encoding/csv"
+	pkgIo      = "io"
+	pkgHttpErr = "github.com/lukasdietrich/flowheater/httperr"
 )
 
 var (
@@ -19,20 +32,51 @@ var (
 	genWrapError      = "wrapError"
 	genRouterReceiver = jen.Id("s").Op("*").Id(genRouter)
 
+	genBindingError   = "BindingError"
+	genStatusError    = "StatusError"
+	genPublicError    = "PublicError"
+	genHttpError      = "HttpError"
+	genProblem        = "Problem"
+	genInvalidParam   = "InvalidParam"
+	genErrMissing     = "ErrMissingParam"
+	genErrUnsupported = "ErrUnsupportedMediaType"
+	genErrAcceptable  = "ErrNotAcceptable"
+	genNegotiate      = "negotiateMediaType"
+	genWriteEvent     = "writeEvent"
+	genErrMethod      = "ErrMethodNotAllowed"
+	genNotAllowed     = "methodNotAllowed"
+	genAllowOptions   = "allowOptions"
+	genHeadHandler    = "headHandler"
+	genStatusWriter   = "statusResponseWriter"
+	genRouterMethods  = "routerMethods"
+
 	genCustomError    = "HandleError"
 	genCustomRequest  = "ReadRequest"
 	genCustomResponse = "WriteResponse"
 )
 
 func RenderServiceRouter(filename string, collection *ServiceCollection) error {
-	renderer := jen.NewFile(collection.PackageName)
+	if err := checkConstraints(collection); err != nil {
+		return err
+	}
+
+	if err := checkWildcards(collection); err != nil {
+		return err
+	}
+
+	renderer := jen.NewFilePathName(collection.PackagePath, collection.PackageName)
 	renderer.HeaderComment("Code generated by flowheater. DO NOT EDIT.")
 
 	for _, part := range []jen.Code{
 		renderCustomFuncTypes(),
 		renderRouterStruct(collection),
 		renderRouterHandler(collection),
+		routerBackends[routerName].Declarations(collection),
 		renderErrorHandler(),
+		renderMethodHandlers(),
+		renderStatusResponseWriter(collection),
+		renderNegotiation(collection),
+		renderWriteEvent(collection),
 		renderRouterEndpoints(collection),
 	} {
 		renderer.Add(part).Line()
@@ -92,7 +136,9 @@ func renderRouterStruct(c *ServiceCollection) jen.Code {
 		Id(genRouter).
 		StructFunc(func(g *jen.Group) {
 			for _, service := range c.Services {
-				g.Id(service.TypeName).Op("*").Id(service.TypeName)
+				if service.Parent == nil {
+					g.Id(service.TypeName).Op("*").Id(service.TypeName)
+				}
 			}
 
 			for _, resolver := range c.Resolvers {
@@ -114,6 +160,8 @@ func renderRouterStruct(c *ServiceCollection) jen.Code {
 }
 
 func renderRouterHandler(c *ServiceCollection) jen.Code {
+	backend := routerBackends[routerName]
+
 	return jen.
 		Comment("Handler creates a new net/http.Handler for all the").Line().
 		Comment("service endpoints.").Line().
@@ -123,12 +171,11 @@ func renderRouterHandler(c *ServiceCollection) jen.Code {
 		Params().
 		Qual(pkgHttp, "Handler").
 		BlockFunc(func(gen *jen.Group) {
-			// h := chi.NewRouter()
-			gen.Id("h").Op(":=").Qual(pkgChi, "NewRouter").Call()
+			backend.NewRouter(gen)
 			gen.Line()
 
 			for _, service := range c.Services {
-				gen.Id("h").Dot("Route").CallFunc(renderRouterRegisterService(service))
+				backend.RegisterService(gen, service)
 				gen.Line()
 			}
 
@@ -137,289 +184,2433 @@ func renderRouterHandler(c *ServiceCollection) jen.Code {
 		})
 }
 
-func renderRouterRegisterService(service *Service) func(*jen.Group) {
-	return func(gen *jen.Group) {
-		gen.Lit(service.Path)
-		gen.Func().Params(jen.Id("r").Qual(pkgChi, "Router")).
-			BlockFunc(func(g *jen.Group) {
-				for _, endpoint := range service.Endpoints {
-					m := strings.Title(strings.ToLower(endpoint.HttpMethod))
-					g.Id("r").Dot(m).Call(
-						jen.Lit(endpoint.Path),
-						jen.Id("s").Dot(genWrapError).Call(
-							jen.Id("s").Dot(endpoint.WrapperFunc()),
-						),
-					)
-				}
-			})
-	}
+// RouterBackend renders the parts of the generated code, that depend on the
+// router the endpoints are registered with.
+type RouterBackend interface {
+	// NewRouter renders the statements creating the router "h".
+	NewRouter(gen *jen.Group)
+
+	// RegisterService registers the endpoints of a service with the router
+	// "h".
+	RegisterService(gen *jen.Group, service *Service)
+
+	// PathParam returns the expression extracting a path parameter from the
+	// request "r".
+	PathParam(param InputParam) jen.Code
+
+	// Constraints reports whether the router supports regular expressions
+	// constraining path parameters.
+	Constraints() bool
+
+	// ExclusiveWildcards reports whether a path parameter cannot share its
+	// position with other path segments.
+	ExclusiveWildcards() bool
+
+	// Declarations returns additional declarations required by the router.
+	Declarations(c *ServiceCollection) jen.Code
 }
 
-func renderErrorHandler() jen.Code {
-	return jen.
-		Comment(genWrapError + " wraps a handler to conform with http.HandlerFunc.").Line().
-		Func().
-		Params(genRouterReceiver).
-		Id(genWrapError).
-		Params(
-			jen.Id("fn").
-				Func().
-				Params(
-					jen.Qual(pkgHttp, "ResponseWriter"),
-					jen.Op("*").Qual(pkgHttp, "Request"),
-				).
-				Params(jen.Id("error")),
-		).
-		Params(jen.Qual(pkgHttp, "HandlerFunc")).
-		Block(
-			jen.Return().
-				Func().
-				Params(
-					jen.Id("w").Qual(pkgHttp, "ResponseWriter"),
-					jen.Id("r").Op("*").Qual(pkgHttp, "Request"),
-				).
-				Block(
-					jen.If(
-						jen.Id("err").Op(":=").Id("fn").Call(
-							jen.Id("w"),
-							jen.Id("r"),
-						),
-						jen.Id("err").Op("!=").Nil(),
-					).BlockFunc(func(gen *jen.Group) {
-						if customErrorHandler {
-							gen.Id("s").Dot(genCustomError).Call(
-								jen.Id("w"),
-								jen.Id("r"),
-								jen.Id("err"),
-							)
-						} else {
-							gen.Qual(pkgLog, "Printf").
-								Call(
-									jen.Lit("%s %s: %v"),
-									jen.Id("r").Dot("Method"),
-									jen.Id("r").Dot("URL").Dot("Path"),
-									jen.Id("err"),
-								)
-							gen.Id("w").Dot("WriteHeader").
-								Call(jen.Qual(pkgHttp, "StatusInternalServerError"))
-						}
-					}),
-				),
-		)
+var routerBackends = map[string]RouterBackend{
+	"chi":        chiBackend{},
+	"servemux":   serveMuxBackend{},
+	"gorilla":    gorillaBackend{},
+	"httprouter": httprouterBackend{},
 }
 
-func renderRouterEndpoints(c *ServiceCollection) jen.Code {
-	var funcs jen.Statement
+// checkConstraints reports path parameters constrained by a regular
+// expression, if the router does not support them.
+func checkConstraints(c *ServiceCollection) error {
+	if routerBackends[routerName].Constraints() {
+		return nil
+	}
 
 	for _, service := range c.Services {
 		for _, endpoint := range service.Endpoints {
-			funcs.Add(renderEndpointWrapper(endpoint))
+			fullPath := joinPath(service.Path, endpoint.Path)
+			if err := checkPathConstraints(fullPath); err != nil {
+				return fmt.Errorf("%s.%s: %v", service.Name(), endpoint.FuncName, err)
+			}
+		}
+
+		for _, handler := range service.Handlers {
+			if err := checkPathConstraints(handler.FullPath); err != nil {
+				return fmt.Errorf("%s.%s: %v", service.Name(), handler.Name, err)
+			}
 		}
 	}
 
-	return &funcs
+	return nil
 }
 
-func renderEndpointWrapper(endpoint *Endpoint) jen.Code {
-	// func (s *ServiceRouter) func _handle_<Service>_<Endpoint>(
-	//   w http.ResponseWriter,
-	//   r *http.Request,
-	// ) { ... }
-	return jen.
-		Commentf("%s wraps the endpoint %s#%s.",
-			endpoint.WrapperFunc(),
-			endpoint.Service.TypeName,
-			endpoint.FuncName,
-		).Line().
-		Func().
-		Params(genRouterReceiver).
-		Id(endpoint.WrapperFunc()).
-		Params(
-			jen.Id("w").Qual(pkgHttp, "ResponseWriter"),
-			jen.Id("r").Op("*").Qual(pkgHttp, "Request"),
-		).
-		Params(jen.Id("error")).
-		BlockFunc(renderEndpointWrapperBody(endpoint)).
-		Line()
+// wildcardRoute is a path registered with a router for some methods.
+type wildcardRoute struct {
+	name    string
+	path    string
+	methods []string
 }
 
-func renderEndpointWrapperBody(endpoint *Endpoint) func(*jen.Group) {
-	return func(gen *jen.Group) {
-		gen.Defer().Id("r").Dot("Body").Dot("Close").Call()
-
-		for _, param := range endpoint.InputParams {
-			// param0 := chi.URLParam("<paramName>")
-			renderInputParam(gen, param)
-		}
+// checkWildcards reports routes and mounted handlers sharing a method, whose
+// path parameters share their position with other path segments, if the
+// router does not support them.
+func checkWildcards(c *ServiceCollection) error {
+	if !routerBackends[routerName].ExclusiveWildcards() {
+		return nil
+	}
 
-		if len(endpoint.InputParams) > 0 {
-			gen.Line()
-		}
+	var routes []wildcardRoute
 
-		// s.<Service>.<Endpoint>(<Params>...)
-		callFunc := jen.Id("s").
-			Dot(endpoint.Service.TypeName).
-			Dot(endpoint.FuncName).
-			Call(renderInputVars(endpoint.InputVars))
+	for _, service := range c.Services {
+		for _, route := range service.Routes() {
+			methods := c.Methods()
 
-		if !endpoint.ReturnsError && !endpoint.ReturnsValue {
-			gen.Add(callFunc)
-			gen.Return().Nil()
-		} else if endpoint.ReturnsError && !endpoint.ReturnsValue {
-			gen.Return().Add(callFunc)
-		} else {
-			gen.
-				ListFunc(func(gen *jen.Group) {
-					if endpoint.ReturnsValue {
-						gen.Id("val")
-					}
+			if !route.AnyMethod() {
+				methods = nil
 
-					if endpoint.ReturnsError {
-						gen.Id("err")
-					}
-				}).
-				Op(":=").
-				Add(callFunc)
+				for _, endpoint := range route.Endpoints {
+					methods = append(methods, endpoint.HttpMethods...)
+				}
 
-			if endpoint.ReturnsError {
-				renderIfErr(gen)
+				if route.hasMethod(http.MethodGet) && !route.hasMethod(http.MethodHead) {
+					methods = append(methods, http.MethodHead)
+				}
 			}
 
-			if customResponseWriter {
-				gen.Return().
-					Id("s").Dot(genCustomResponse).Call(
-					jen.Id("w"),
-					jen.Id("r"),
-					jen.Id("val"),
-				)
-			} else {
-				gen.Return().
-					Qual(pkgJson, "NewEncoder").Call(jen.Id("w")).
-					Dot("Encode").Call(jen.Id("val"))
-			}
+			routes = append(routes, wildcardRoute{
+				name:    fmt.Sprintf("%s.%s", service.Name(), route.Endpoints[0].FuncName),
+				path:    route.FullPath,
+				methods: methods,
+			})
+		}
+
+		// Mounted handlers are registered with a catch-all for every method.
+		for _, handler := range service.Handlers {
+			routes = append(routes, wildcardRoute{
+				name:    fmt.Sprintf("%s.%s", service.Name(), handler.Name),
+				path:    mountPattern(handler.FullPath) + "*",
+				methods: c.Methods(),
+			})
 		}
 	}
-}
 
-func renderInputParam(gen *jen.Group, param InputParam) {
-	switch param.ParamKind {
-	case KindStringParam:
-		renderStringParam(gen, param)
+	for i, route := range routes {
+		for _, other := range routes[:i] {
+			if !sharesMethod(route.methods, other.methods) {
+				continue
+			}
 
-	case KindConvertParam:
-		renderConvertParam(gen, param)
+			if segment, ok := conflictingSegment(route.path, other.path); ok {
+				return fmt.Errorf("route %s of %s conflicts with route %s of %s at %q, "+
+					"since path parameters cannot share their position with other segments",
+					route.path, route.name, other.path, other.name, segment)
+			}
+		}
+	}
 
-	case KindPayloadParam:
-		renderPayloadParam(gen, param)
+	return nil
+}
 
-	case KindResolveParam:
-		renderResolverParam(gen, param)
+func sharesMethod(a, b []string) bool {
+	for _, method := range a {
+		if containsString(b, method) {
+			return true
+		}
 	}
 
-	gen.Line()
+	return false
 }
 
-func renderStringParam(gen *jen.Group, param InputParam) {
-	gen.Commentf("Extract url parameter %s.", param.ParamName)
-	gen.Id(param.VarName).Op(":=").Qual(pkgChi, "URLParam").Call(
-		jen.Id("r"),
-		jen.Lit(param.ParamName),
+// conflictingSegment compares the segments of two paths up to the first
+// different one. If a path parameter starts before the segments diverge, the
+// segment is returned.
+func conflictingSegment(a, b string) (string, bool) {
+	var (
+		aSegments = strings.Split(a, "/")
+		bSegments = strings.Split(b, "/")
 	)
-}
-
-func renderConvertParam(gen *jen.Group, param InputParam) {
-	stringVar := param.InputVars[0]
 
-	gen.Commentf("Convert %s to %s.", stringVar.VarName, param.TypeName)
-
-	switch param.TypeName {
-	case "int", "int8", "int16", "int32", "int64",
-		"uint", "uint8", "uint16", "uint32", "uint64":
+	for i := 0; i < len(aSegments) && i < len(bSegments); i++ {
+		aSegment, bSegment := aSegments[i], bSegments[i]
+		if aSegment == bSegment {
+			continue
+		}
 
 		var (
-			unsizedType = strings.TrimRight(param.TypeName, "123468")
-			parseFunc   = "Parse" + strings.Title(unsizedType)
-			tempName    = param.VarName + "b64"
+			aLiteral = aSegment
+			bLiteral = bSegment
 		)
 
-		gen.List(jen.Id(tempName), jen.Id("err")).
-			Op(":=").
-			Qual(pkgStrconv, parseFunc).
-			Call(jen.Id(stringVar.VarName), jen.Lit(10), jen.Lit(64))
+		if start := strings.IndexAny(aSegment, "{*"); start >= 0 {
+			aLiteral = aSegment[:start]
+		}
 
-		renderIfErr(gen)
-		gen.Id(param.VarName).Op(":=").Id(param.TypeName).Call(jen.Id(tempName))
+		if start := strings.IndexAny(bSegment, "{*"); start >= 0 {
+			bLiteral = bSegment[:start]
+		}
 
-	case "bool":
-		gen.List(jen.Id(param.VarName), jen.Id("err")).
-			Op(":=").
-			Qual(pkgStrconv, "ParseBool").
-			Call(jen.Id(stringVar.VarName))
+		if aLiteral == aSegment && bLiteral == bSegment {
+			return "", false
+		}
 
-		renderIfErr(gen)
+		if strings.HasPrefix(aLiteral, bLiteral) || strings.HasPrefix(bLiteral, aLiteral) {
+			return strings.Join(aSegments[:i+1], "/"), true
+		}
+
+		return "", false
 	}
+
+	return "", false
 }
 
-func renderPayloadParam(gen *jen.Group, param InputParam) {
-	gen.Var().Id(param.VarName).Id(param.TypeName)
+func checkPathConstraints(fullPath string) error {
+	params, _ := pathParams(fullPath)
 
-	var decoderCall jen.Code
+	for _, param := range params {
+		if param.Pattern != "" {
+			return fmt.Errorf("router %s does not support the pattern of path parameter {%s} in %s",
+				routerName, param.Name, fullPath)
+		}
+	}
 
-	if customRequestReader {
-		decoderCall = jen.Id("s").Dot(genCustomRequest).Call(
-			jen.Id("r"),
-			jen.Op("&").Id(param.VarName),
-		)
-	} else {
-		decoderCall = jen.
-			Qual(pkgJson, "NewDecoder").Call(jen.Id("r").Dot("Body")).
-			Dot("Decode").Call(jen.Op("&").Id(param.VarName))
+	return nil
+}
+
+// wildcardName is the name of a "*" catch-all path parameter for routers,
+// that require every parameter to be named.
+const wildcardName = "wildcard"
+
+func namedParam(name string) string {
+	if name == "*" {
+		return wildcardName
 	}
 
-	gen.If(
-		jen.Id("err").Op(":=").Add(decoderCall),
-		jen.Id("err").Op("!=").Nil(),
-	).Block(jen.Return().Id("err"))
+	return name
 }
 
-func renderResolverParam(gen *jen.Group, param InputParam) {
-	gen.Commentf("Resolve parameter using %s.", param.Resolver)
+// renderEndpointHandler returns the endpoint wrapper as a http.HandlerFunc.
+func renderEndpointHandler(endpoint *Endpoint) jen.Code {
+	return jen.Id("s").Dot(genWrapError).Call(
+		jen.Id("s").Dot(endpoint.WrapperFunc()),
+	)
+}
 
-	var stmt jen.Code
+// renderMountedHandler returns the value of a mounted handler. Unless
+// disabled, the path of the handler is stripped from requests.
+func renderMountedHandler(handler *MountedHandler) jen.Code {
+	// <Service>.<Name>
+	value := renderService(handler.Service).Dot(handler.Name)
+	if handler.Method {
+		value.Call()
+	}
 
-	if param.ReturnsError {
-		stmt = jen.List(jen.Id(param.VarName), jen.Id("err"))
-	} else {
-		stmt = jen.Id(param.VarName)
+	if !handler.StripPrefix || handler.FullPath == "/" {
+		return value
 	}
 
-	gen.Add(stmt).
-		Op(":=").
-		Id("s").Dot(param.Resolver).Dot(mResolve).
-		Call(renderInputVars(param.InputVars))
+	// http.StripPrefix("<path>", <value>)
+	return jen.Qual(pkgHttp, "StripPrefix").Call(jen.Lit(handler.FullPath), value)
+}
 
-	if param.ReturnsError {
-		renderIfErr(gen)
-	}
+// mountPattern returns the path of a mounted handler with a trailing slash,
+// which routers use to match the subtree below the path.
+func mountPattern(p string) string {
+	return strings.TrimSuffix(p, "/") + "/"
 }
 
-func renderIfErr(gen *jen.Group) {
-	gen.If(jen.Id("err").Op("!=").Nil()).Block(jen.Return().Id("err"))
+// routeHandler is a handler registered for a method of a route.
+type routeHandler struct {
+	method  string
+	handler jen.Code
 }
 
-func renderInputVars(inputVars []InputVar) jen.Code {
-	var varsCode []jen.Code
+// routeHandlers returns the handlers of a route. Unless declared explicitly,
+// the implicit methods are handled as well: HEAD requests by the GET endpoint
+// without writing the body and OPTIONS requests by answering with the
+// allowed methods.
+func routeHandlers(route Route, implicitMethods ...string) []routeHandler {
+	var handlers []routeHandler
 
-	for _, inputVar := range inputVars {
-		var prefix string
-		if n := inputVar.PointerDepth; n > 0 {
-			prefix = strings.Repeat("&", n)
-		} else {
-			prefix = strings.Repeat("*", -n)
+	for _, endpoint := range route.Endpoints {
+		for _, method := range endpoint.HttpMethods {
+			handlers = append(handlers, routeHandler{method, renderEndpointHandler(endpoint)})
 		}
+	}
 
-		varsCode = append(varsCode, jen.Id(prefix+inputVar.VarName))
+	if route.AnyMethod() {
+		return handlers
 	}
 
-	return jen.List(varsCode...)
+	for _, method := range implicitMethods {
+		if route.hasMethod(method) {
+			continue
+		}
+
+		switch method {
+		case http.MethodHead:
+			if get, ok := route.Endpoint(http.MethodGet); ok {
+				handlers = append(handlers, routeHandler{
+					method,
+					jen.Id(genHeadHandler).Call(renderEndpointHandler(get)),
+				})
+			}
+
+		case http.MethodOptions:
+			handlers = append(handlers, routeHandler{
+				method,
+				jen.Id(genAllowOptions).Call(renderAllow(route)),
+			})
+		}
+	}
+
+	return handlers
+}
+
+// renderNotAllowedHandler returns the handler rejecting methods, which are
+// not allowed for the route.
+func renderNotAllowedHandler(route Route) jen.Code {
+	// s.methodNotAllowed("<allow>")
+	return jen.Id("s").Dot(genNotAllowed).Call(renderAllow(route))
+}
+
+func renderAllow(route Route) jen.Code {
+	return jen.Lit(strings.Join(route.AllowedMethods(), ", "))
+}
+
+// chiBackend registers endpoints with a chi.Router, routing each service in
+// a sub router.
+type chiBackend struct{}
+
+func (chiBackend) NewRouter(gen *jen.Group) {
+	// h := chi.NewRouter()
+	gen.Id("h").Op(":=").Qual(pkgChi, "NewRouter").Call()
+}
+
+// chiMethods are the methods of a chi.Router registering a handler for a
+// standard http method.
+var chiMethods = map[string]string{
+	http.MethodGet:     "Get",
+	http.MethodHead:    "Head",
+	http.MethodPost:    "Post",
+	http.MethodPut:     "Put",
+	http.MethodPatch:   "Patch",
+	http.MethodDelete:  "Delete",
+	http.MethodConnect: "Connect",
+	http.MethodOptions: "Options",
+	http.MethodTrace:   "Trace",
+}
+
+// RegisterService registers the handler for methods, which are not allowed,
+// first. chi replaces it for the methods registered afterwards.
+func (chiBackend) RegisterService(gen *jen.Group, service *Service) {
+	gen.Id("h").Dot("Route").Call(
+		jen.Lit(chiPath(service.Path)),
+		jen.Func().Params(jen.Id("r").Qual(pkgChi, "Router")).
+			BlockFunc(func(g *jen.Group) {
+				for _, route := range service.Routes() {
+					path := jen.Lit(chiPath(route.Path))
+
+					if !route.AnyMethod() {
+						g.Id("r").Dot("HandleFunc").Call(path, renderNotAllowedHandler(route))
+					}
+
+					for _, h := range routeHandlers(route, http.MethodHead, http.MethodOptions) {
+						if h.method == MethodAny {
+							g.Id("r").Dot("HandleFunc").Call(path, h.handler)
+						} else if m, ok := chiMethods[h.method]; ok {
+							g.Id("r").Dot(m).Call(path, h.handler)
+						} else {
+							g.Id("r").Dot("MethodFunc").Call(jen.Lit(h.method), path, h.handler)
+						}
+					}
+				}
+
+				for _, handler := range service.Handlers {
+					// r.Mount("<path>", <handler>)
+					g.Id("r").Dot("Mount").Call(jen.Lit(chiPath(handler.Path)), renderMountedHandler(handler))
+				}
+			}),
+	)
+}
+
+func (chiBackend) PathParam(param InputParam) jen.Code {
+	name := param.ParamKey
+	if param.CatchAll {
+		name = "*"
+	}
+
+	// chi.URLParam(r, "<name>")
+	return jen.Qual(pkgChi, "URLParam").Call(jen.Id("r"), jen.Lit(name))
+}
+
+func (chiBackend) Constraints() bool {
+	return true
+}
+
+func (chiBackend) ExclusiveWildcards() bool {
+	return false
+}
+
+// chiPath translates a path to the syntax of chi, which only supports "*" as
+// a catch-all.
+func chiPath(p string) string {
+	return formatPath(p, func(param PathParam) string {
+		switch {
+		case param.CatchAll:
+			return "*"
+
+		case param.Pattern != "":
+			return "{" + param.Name + ":" + param.Pattern + "}"
+
+		default:
+			return "{" + param.Name + "}"
+		}
+	})
+}
+
+// Declarations registers custom methods, which chi does not know by default.
+// Endpoints accepting any method require all known methods to be registered.
+func (chiBackend) Declarations(c *ServiceCollection) jen.Code {
+	var methods []string
+
+	for _, service := range c.Services {
+		for _, endpoint := range service.Endpoints {
+			declared := endpoint.HttpMethods
+			if endpoint.AnyMethod() {
+				declared = HttpMethods
+			}
+
+			for _, method := range declared {
+				if _, ok := chiMethods[method]; !ok && !containsString(methods, method) {
+					methods = append(methods, method)
+				}
+			}
+		}
+	}
+
+	if len(methods) == 0 {
+		return jen.Null()
+	}
+
+	return jen.
+		Comment("init registers the custom http methods with chi.").Line().
+		Func().Id("init").Params().BlockFunc(func(g *jen.Group) {
+		for _, method := range methods {
+			g.Qual(pkgChi, "RegisterMethod").Call(jen.Lit(method))
+		}
+	})
+}
+
+// serveMuxBackend registers endpoints with a http.ServeMux using the method
+// and wildcard patterns introduced in go 1.22. HEAD requests and methods,
+// which are not allowed, are handled by the http.ServeMux itself, since the
+// patterns would conflict with the patterns of other routes otherwise.
+type serveMuxBackend struct{}
+
+func (serveMuxBackend) NewRouter(gen *jen.Group) {
+	// h := http.NewServeMux()
+	gen.Id("h").Op(":=").Qual(pkgHttp, "NewServeMux").Call()
+}
+
+func (serveMuxBackend) RegisterService(gen *jen.Group, service *Service) {
+	for _, route := range service.Routes() {
+		pattern := serveMuxPath(route.FullPath)
+
+		// Patterns ending in a slash would match the whole subtree otherwise.
+		if strings.HasSuffix(pattern, "/") {
+			pattern += "{$}"
+		}
+
+		for _, h := range routeHandlers(route, http.MethodOptions) {
+			if h.method == MethodAny {
+				gen.Id("h").Dot("HandleFunc").Call(jen.Lit(pattern), h.handler)
+			} else {
+				gen.Id("h").Dot("HandleFunc").Call(jen.Lit(h.method+" "+pattern), h.handler)
+			}
+		}
+	}
+
+	// Patterns ending in a slash match the whole subtree. Requests for the
+	// path without the slash are redirected by the http.ServeMux.
+	for _, handler := range service.Handlers {
+		pattern := serveMuxPath(mountPattern(handler.FullPath))
+		gen.Id("h").Dot("Handle").Call(jen.Lit(pattern), renderMountedHandler(handler))
+	}
+}
+
+func (serveMuxBackend) PathParam(param InputParam) jen.Code {
+	// r.PathValue("<name>")
+	return jen.Id("r").Dot("PathValue").Call(jen.Lit(serveMuxName(param.ParamKey)))
+}
+
+func (serveMuxBackend) Constraints() bool {
+	return false
+}
+
+func (serveMuxBackend) ExclusiveWildcards() bool {
+	return false
+}
+
+// serveMuxPath translates a path to the syntax of http.ServeMux.
+func serveMuxPath(p string) string {
+	return formatPath(p, func(param PathParam) string {
+		if param.CatchAll {
+			return "{" + serveMuxName(param.Name) + "...}"
+		}
+
+		return "{" + serveMuxName(param.Name) + "}"
+	})
+}
+
+// serveMuxName returns a valid wildcard name of http.ServeMux, which only
+// accepts go identifiers.
+func serveMuxName(name string) string {
+	return strings.Replace(namedParam(name), "-", "_", -1)
+}
+
+func (serveMuxBackend) Declarations(c *ServiceCollection) jen.Code {
+	return jen.Null()
+}
+
+// gorillaBackend registers endpoints with a mux.Router of gorilla/mux. Routes
+// are matched in order, so the route handling the methods, which are not
+// allowed, is registered last.
+type gorillaBackend struct{}
+
+func (gorillaBackend) NewRouter(gen *jen.Group) {
+	// h := mux.NewRouter()
+	gen.Id("h").Op(":=").Qual(pkgMux, "NewRouter").Call()
+}
+
+func (gorillaBackend) RegisterService(gen *jen.Group, service *Service) {
+	for _, route := range service.Routes() {
+		path := jen.Lit(gorillaPath(route.FullPath))
+
+		for _, h := range routeHandlers(route, http.MethodHead, http.MethodOptions) {
+			stmt := gen.Id("h").Dot("HandleFunc").Call(path, h.handler)
+
+			if h.method != MethodAny {
+				stmt.Dot("Methods").Call(jen.Lit(h.method))
+			}
+		}
+
+		if !route.AnyMethod() {
+			gen.Id("h").Dot("HandleFunc").Call(path, renderNotAllowedHandler(route))
+		}
+	}
+
+	// A mounted handler is registered for its path and as a prefix of the
+	// subtree below it, which excludes paths merely starting with the same
+	// characters.
+	for _, handler := range service.Handlers {
+		// {
+		//     handler := <handler>
+		//     h.Handle("<path>", handler)
+		//     h.PathPrefix("<path>/").Handler(handler)
+		// }
+		gen.BlockFunc(func(g *jen.Group) {
+			g.Id("handler").Op(":=").Add(renderMountedHandler(handler))
+
+			if handler.FullPath != "/" {
+				g.Id("h").Dot("Handle").Call(jen.Lit(gorillaPath(handler.FullPath)), jen.Id("handler"))
+			}
+
+			g.Id("h").Dot("PathPrefix").Call(jen.Lit(gorillaPath(mountPattern(handler.FullPath)))).
+				Dot("Handler").Call(jen.Id("handler"))
+		})
+	}
+}
+
+func (gorillaBackend) PathParam(param InputParam) jen.Code {
+	// mux.Vars(r)["<name>"]
+	return jen.Qual(pkgMux, "Vars").Call(jen.Id("r")).Index(jen.Lit(namedParam(param.ParamKey)))
+}
+
+func (gorillaBackend) Constraints() bool {
+	return true
+}
+
+func (gorillaBackend) ExclusiveWildcards() bool {
+	return false
+}
+
+// gorillaPath translates a path to the syntax of gorilla/mux. A catch-all is
+// a variable matching any character including slashes.
+func gorillaPath(p string) string {
+	return formatPath(p, func(param PathParam) string {
+		switch {
+		case param.CatchAll:
+			return "{" + namedParam(param.Name) + ":.*}"
+
+		case param.Pattern != "":
+			return "{" + param.Name + ":" + param.Pattern + "}"
+
+		default:
+			return "{" + param.Name + "}"
+		}
+	})
+}
+
+func (gorillaBackend) Declarations(c *ServiceCollection) jen.Code {
+	return jen.Null()
+}
+
+// httprouterBackend registers endpoints with a httprouter.Router. Path
+// parameters are translated to the syntax of httprouter, e.g. "/:id". Since
+// there is no catch-all for methods, endpoints accepting any method are
+// registered for every known method.
+type httprouterBackend struct{}
+
+// NewRouter handles OPTIONS requests and methods, which are not allowed, with
+// the handlers of httprouter. They set the Allow header of the response.
+func (httprouterBackend) NewRouter(gen *jen.Group) {
+	// h := httprouter.New()
+	gen.Id("h").Op(":=").Qual(pkgRouter, "New").Call()
+
+	// h.GlobalOPTIONS = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	//     w.WriteHeader(http.StatusNoContent)
+	// })
+	gen.Id("h").Dot("GlobalOPTIONS").Op("=").Qual(pkgHttp, "HandlerFunc").Call(
+		jen.Func().Params(
+			jen.Id("w").Qual(pkgHttp, "ResponseWriter"),
+			jen.Id("r").Op("*").Qual(pkgHttp, "Request"),
+		).Block(
+			jen.Id("w").Dot("WriteHeader").Call(jen.Qual(pkgHttp, "StatusNoContent")),
+		),
+	)
+
+	// h.MethodNotAllowed = s.wrapError(func(w http.ResponseWriter, r *http.Request) error {
+	//     return ErrMethodNotAllowed
+	// })
+	gen.Id("h").Dot("MethodNotAllowed").Op("=").Id("s").Dot(genWrapError).Call(
+		jen.Func().Params(
+			jen.Id("w").Qual(pkgHttp, "ResponseWriter"),
+			jen.Id("r").Op("*").Qual(pkgHttp, "Request"),
+		).Error().Block(
+			jen.Return().Qual(pkgHttpErr, genErrMethod),
+		),
+	)
+}
+
+func (httprouterBackend) RegisterService(gen *jen.Group, service *Service) {
+	for _, route := range service.Routes() {
+		pattern := jen.Lit(httprouterPath(route.FullPath))
+
+		for _, h := range routeHandlers(route, http.MethodHead) {
+			if h.method != MethodAny {
+				gen.Id("h").Dot("HandlerFunc").Call(jen.Lit(h.method), pattern, h.handler)
+				continue
+			}
+
+			// for _, method := range routerMethods {
+			//     h.HandlerFunc(method, "<pattern>", handler)
+			// }
+			gen.For(jen.List(jen.Id("_"), jen.Id("method")).Op(":=").Range().Id(genRouterMethods)).Block(
+				jen.Id("h").Dot("HandlerFunc").Call(jen.Id("method"), pattern, h.handler),
+			)
+		}
+	}
+
+	// A mounted handler is registered for every method with a catch-all
+	// below its path. Requests for the path itself are redirected by
+	// httprouter.
+	for _, handler := range service.Handlers {
+		pattern := jen.Lit(httprouterPath(mountPattern(handler.FullPath)) + "*" + wildcardName)
+
+		// {
+		//     handler := <handler>
+		//     for _, method := range routerMethods {
+		//         h.Handler(method, "<pattern>", handler)
+		//     }
+		// }
+		gen.Block(
+			jen.Id("handler").Op(":=").Add(renderMountedHandler(handler)),
+			jen.For(jen.List(jen.Id("_"), jen.Id("method")).Op(":=").Range().Id(genRouterMethods)).Block(
+				jen.Id("h").Dot("Handler").Call(jen.Id("method"), pattern, jen.Id("handler")),
+			),
+		)
+	}
+}
+
+// PathParam trims the leading slash of a catch-all, which httprouter
+// includes in the value unlike the other routers.
+func (httprouterBackend) PathParam(param InputParam) jen.Code {
+	// httprouter.ParamsFromContext(r.Context()).ByName("<name>")
+	value := jen.Qual(pkgRouter, "ParamsFromContext").
+		Call(jen.Id("r").Dot("Context").Call()).
+		Dot("ByName").Call(jen.Lit(namedParam(param.ParamKey)))
+
+	if param.CatchAll {
+		// strings.TrimPrefix(<value>, "/")
+		return jen.Qual(pkgStrings, "TrimPrefix").Call(value, jen.Lit("/"))
+	}
+
+	return value
+}
+
+func (httprouterBackend) Constraints() bool {
+	return false
+}
+
+// ExclusiveWildcards is true, since httprouter panics, if a path parameter
+// shares its position with another segment, e.g. "/users/:id" and
+// "/users/new", or if a catch-all shares it with anything else.
+func (httprouterBackend) ExclusiveWildcards() bool {
+	return true
+}
+
+// httprouterPath translates a path to the syntax of httprouter, e.g.
+// "/:id" and "/*rest".
+func httprouterPath(p string) string {
+	return formatPath(p, func(param PathParam) string {
+		if param.CatchAll {
+			return "*" + namedParam(param.Name)
+		}
+
+		return ":" + param.Name
+	})
+}
+
+// Declarations lists the methods, that paths accepting any method are
+// registered for, since httprouter requires a method for every handler.
+func (httprouterBackend) Declarations(c *ServiceCollection) jen.Code {
+	required := false
+
+	for _, service := range c.Services {
+		if len(service.Handlers) > 0 {
+			required = true
+		}
+
+		for _, endpoint := range service.Endpoints {
+			if endpoint.AnyMethod() {
+				required = true
+			}
+		}
+	}
+
+	if !required {
+		return jen.Null()
+	}
+
+	return jen.
+		Comment(genRouterMethods + " are the known methods and the custom methods of all endpoints.").Line().
+		Var().Id(genRouterMethods).Op("=").Add(renderStrings(c.Methods()))
+}
+
+// renderStrings renders a string slice literal.
+func renderStrings(values []string) jen.Code {
+	return jen.Index().String().ValuesFunc(func(g *jen.Group) {
+		for _, value := range values {
+			g.Lit(value)
+		}
+	})
+}
+
+func renderErrorHandler() jen.Code {
+	return jen.
+		Comment(genWrapError + " wraps a handler to conform with http.HandlerFunc.").Line().
+		Func().
+		Params(genRouterReceiver).
+		Id(genWrapError).
+		Params(
+			jen.Id("fn").
+				Func().
+				Params(
+					jen.Qual(pkgHttp, "ResponseWriter"),
+					jen.Op("*").Qual(pkgHttp, "Request"),
+				).
+				Params(jen.Id("error")),
+		).
+		Params(jen.Qual(pkgHttp, "HandlerFunc")).
+		Block(
+			jen.Return().
+				Func().
+				Params(
+					jen.Id("w").Qual(pkgHttp, "ResponseWriter"),
+					jen.Id("r").Op("*").Qual(pkgHttp, "Request"),
+				).
+				Block(
+					jen.If(
+						jen.Id("err").Op(":=").Id("fn").Call(
+							jen.Id("w"),
+							jen.Id("r"),
+						),
+						jen.Id("err").Op("!=").Nil(),
+					).BlockFunc(func(gen *jen.Group) {
+						if customErrorHandler {
+							gen.Id("s").Dot(genCustomError).Call(
+								jen.Id("w"),
+								jen.Id("r"),
+								jen.Id("err"),
+							)
+						} else if problemDetails {
+							renderProblemErrorHandler(gen)
+						} else {
+							renderStatusErrorHandler(gen)
+
+							gen.Qual(pkgLog, "Printf").
+								Call(
+									jen.Lit("%s %s: %v"),
+									jen.Id("r").Dot("Method"),
+									jen.Id("r").Dot("URL").Dot("Path"),
+									jen.Id("err"),
+								)
+							gen.Id("w").Dot("WriteHeader").
+								Call(jen.Qual(pkgHttp, "StatusInternalServerError"))
+						}
+					}),
+				),
+		)
+}
+
+// renderMethodHandlers renders the handlers registered for methods, which
+// are not declared by the endpoints of a route.
+func renderMethodHandlers() jen.Code {
+	handlerFunc := jen.Qual(pkgHttp, "HandlerFunc")
+	handlerParams := jen.Params(
+		jen.Id("w").Qual(pkgHttp, "ResponseWriter"),
+		jen.Id("r").Op("*").Qual(pkgHttp, "Request"),
+	)
+
+	return jen.Add(
+		jen.Comment(genNotAllowed+" rejects the methods, which are not allowed for").Line().
+			Comment("a route.").Line().
+			Func().Params(genRouterReceiver).Id(genNotAllowed).
+			Params(jen.Id("allow").String()).
+			Add(handlerFunc).
+			Block(
+				jen.Return().Id("s").Dot(genWrapError).Call(
+					jen.Func().Add(handlerParams).Error().Block(
+						jen.Id("w").Dot("Header").Call().Dot("Set").Call(jen.Lit("Allow"), jen.Id("allow")),
+						jen.Return().Qual(pkgHttpErr, genErrMethod),
+					),
+				),
+			).
+			Line().Line(),
+
+		jen.Comment(genAllowOptions+" answers OPTIONS requests with the allowed methods.").Line().
+			Func().Id(genAllowOptions).
+			Params(jen.Id("allow").String()).
+			Add(handlerFunc).
+			Block(
+				jen.Return().Func().Add(handlerParams).Block(
+					jen.Id("w").Dot("Header").Call().Dot("Set").Call(jen.Lit("Allow"), jen.Id("allow")),
+					jen.Id("w").Dot("WriteHeader").Call(jen.Qual(pkgHttp, "StatusNoContent")),
+				),
+			).
+			Line().Line(),
+
+		jen.Comment(genHeadHandler+" answers HEAD requests with a GET handler, discarding").Line().
+			Comment("the body of the response.").Line().
+			Func().Id(genHeadHandler).
+			Params(jen.Id("h").Add(handlerFunc)).
+			Add(handlerFunc).
+			Block(
+				jen.Return().Func().Add(handlerParams).Block(
+					jen.Id("h").Call(
+						jen.Id("headResponseWriter").Values(jen.Id("w")),
+						jen.Id("r"),
+					),
+				),
+			).
+			Line().Line(),
+
+		jen.Type().Id("headResponseWriter").Struct(
+			jen.Qual(pkgHttp, "ResponseWriter"),
+		).
+			Line().Line(),
+
+		jen.Func().Params(jen.Id("headResponseWriter")).Id("Write").
+			Params(jen.Id("p").Index().Byte()).
+			Params(jen.Int(), jen.Error()).
+			Block(
+				jen.Return(jen.Len(jen.Id("p")), jen.Nil()),
+			),
+	)
+}
+
+// renderNegotiation renders a function to select the media type of a
+// response, if any endpoint returns a value.
+func renderNegotiation(c *ServiceCollection) jen.Code {
+	if customResponseWriter || !anyEndpoint(c, func(e *Endpoint) bool { return len(e.Produces) > 0 }) {
+		return jen.Null()
+	}
+
+	wildcard := jen.Qual(pkgStrings, "HasSuffix").Call(jen.Id("mediaType"), jen.Lit("/*")).
+		Op("&&").
+		Qual(pkgStrings, "HasPrefix").Call(
+		jen.Id("offer"),
+		jen.Id("mediaType").Index(jen.Empty(), jen.Len(jen.Id("mediaType")).Op("-").Lit(1)),
+	)
+
+	return jen.
+		Comment(genNegotiate+" selects the offered media type, that is preferred").Line().
+		Comment("by the Accept header of the request. An empty string is returned").Line().
+		Comment("if none of the offers is acceptable.").Line().
+		Func().
+		Id(genNegotiate).
+		Params(
+			jen.Id("r").Op("*").Qual(pkgHttp, "Request"),
+			jen.Id("offers").Op("...").String(),
+		).
+		String().
+		Block(
+			jen.Id("accept").Op(":=").Id("r").Dot("Header").Dot("Get").Call(jen.Lit("Accept")),
+			jen.If(jen.Id("accept").Op("==").Lit("")).Block(
+				jen.Return().Id("offers").Index(jen.Lit(0)),
+			),
+			jen.Line(),
+
+			jen.Var().Defs(
+				jen.Id("best").String(),
+				jen.Id("bestQ").Float64(),
+			),
+			jen.Line(),
+
+			jen.For(
+				jen.List(jen.Id("_"), jen.Id("part")).Op(":=").Range().
+					Qual(pkgStrings, "Split").Call(jen.Id("accept"), jen.Lit(",")),
+			).Block(
+				jen.List(jen.Id("mediaType"), jen.Id("params"), jen.Id("err")).Op(":=").
+					Qual(pkgMime, "ParseMediaType").Call(jen.Id("part")),
+				jen.If(jen.Id("err").Op("!=").Nil()).Block(jen.Continue()),
+				jen.Line(),
+
+				jen.Id("q").Op(":=").Lit(1.0),
+				jen.If(
+					jen.List(jen.Id("value"), jen.Id("ok")).Op(":=").Id("params").Index(jen.Lit("q")),
+					jen.Id("ok"),
+				).Block(
+					jen.If(
+						jen.List(jen.Id("q"), jen.Id("err")).Op("=").
+							Qual(pkgStrconv, "ParseFloat").Call(jen.Id("value"), jen.Lit(64)),
+						jen.Id("err").Op("!=").Nil(),
+					).Block(jen.Continue()),
+				),
+				jen.Line(),
+
+				jen.If(jen.Id("q").Op("<=").Id("bestQ")).Block(jen.Continue()),
+				jen.Line(),
+
+				jen.For(
+					jen.List(jen.Id("_"), jen.Id("offer")).Op(":=").Range().Id("offers"),
+				).Block(
+					jen.If(
+						jen.Id("mediaType").Op("==").Id("offer").
+							Op("||").
+							Id("mediaType").Op("==").Lit("*/*").
+							Op("||").Line().
+							Add(wildcard),
+					).Block(
+						jen.List(jen.Id("best"), jen.Id("bestQ")).Op("=").
+							List(jen.Id("offer"), jen.Id("q")),
+						jen.Break(),
+					),
+				),
+			),
+			jen.Line(),
+
+			jen.Return().Id("best"),
+		)
+}
+
+func renderWriteEvent(c *ServiceCollection) jen.Code {
+	if !anyEndpoint(c, func(e *Endpoint) bool { return e.Stream == StreamEvents }) {
+		return jen.Null()
+	}
+
+	return jen.
+		Comment(genWriteEvent+" writes a value encoded as json as a server-sent event").Line().
+		Comment("and flushes the response.").Line().
+		Func().
+		Id(genWriteEvent).
+		Params(
+			jen.Id("w").Qual(pkgHttp, "ResponseWriter"),
+			jen.Id("event").Interface(),
+		).
+		Error().
+		Block(
+			jen.List(jen.Id("data"), jen.Id("err")).Op(":=").
+				Qual(pkgJson, "Marshal").Call(jen.Id("event")),
+			jen.If(jen.Id("err").Op("!=").Nil()).Block(jen.Return().Id("err")),
+			jen.Line(),
+
+			jen.If(
+				jen.List(jen.Id("_"), jen.Id("err")).Op(":=").
+					Qual(pkgFmt, "Fprintf").Call(jen.Id("w"), jen.Lit("data: %s\n\n"), jen.Id("data")),
+				jen.Id("err").Op("!=").Nil(),
+			).Block(jen.Return().Id("err")),
+			jen.Line(),
+
+			renderFlush(),
+			jen.Return().Nil(),
+		)
+}
+
+func renderFlush() jen.Code {
+	return jen.If(
+		jen.List(jen.Id("flusher"), jen.Id("ok")).Op(":=").
+			Id("w").Assert(jen.Qual(pkgHttp, "Flusher")),
+		jen.Id("ok"),
+	).Block(jen.Id("flusher").Dot("Flush").Call())
+}
+
+func anyEndpoint(c *ServiceCollection, fn func(*Endpoint) bool) bool {
+	for _, service := range c.Services {
+		for _, endpoint := range service.Endpoints {
+			if fn(endpoint) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// renderProblemErrorHandler responds to errors with a problem document. The
+// status code and detail are taken from errors implementing StatusError and
+// PublicError.
+func renderProblemErrorHandler(gen *jen.Group) {
+	gen.Id("problem").Op(":=").Qual(pkgHttpErr, genProblem).Values(jen.Dict{
+		jen.Id("Type"):     jen.Lit("about:blank"),
+		jen.Id("Status"):   jen.Qual(pkgHttp, "StatusInternalServerError"),
+		jen.Id("Instance"): jen.Id("r").Dot("URL").Dot("Path"),
+	})
+	gen.Line()
+
+	gen.Var().Id("statusErr").Qual(pkgHttpErr, genStatusError)
+	gen.If(
+		jen.Qual(pkgErrors, "As").Call(
+			jen.Id("err"),
+			jen.Op("&").Id("statusErr"),
+		),
+	).Block(
+		jen.Id("problem").Dot("Status").Op("=").Id("statusErr").Dot("StatusCode").Call(),
+		jen.Line(),
+		jen.If(
+			jen.List(jen.Id("publicErr"), jen.Id("ok")).Op(":=").
+				Id("statusErr").Assert(jen.Qual(pkgHttpErr, genPublicError)),
+			jen.Id("ok"),
+		).Block(
+			jen.Id("problem").Dot("Detail").Op("=").Id("publicErr").Dot("PublicMessage").Call(),
+		),
+	)
+	gen.Line()
+
+	gen.Var().Id("bindingErr").Op("*").Qual(pkgHttpErr, genBindingError)
+	gen.If(
+		jen.Qual(pkgErrors, "As").Call(
+			jen.Id("err"),
+			jen.Op("&").Id("bindingErr"),
+		).Op("&&").Id("bindingErr").Dot("Name").Op("!=").Lit(""),
+	).Block(
+		jen.Id("problem").Dot("InvalidParams").Op("=").Index().Qual(pkgHttpErr, genInvalidParam).Values(
+			jen.Values(jen.Dict{
+				jen.Id("Name"):   jen.Id("bindingErr").Dot("Name"),
+				jen.Id("In"):     jen.Id("bindingErr").Dot("Source"),
+				jen.Id("Reason"): jen.Id("bindingErr").Dot("Reason").Call(),
+			}),
+		),
+	)
+	gen.Line()
+
+	gen.If(jen.Id("problem").Dot("Status").Op(">=").Qual(pkgHttp, "StatusInternalServerError")).Block(
+		jen.Qual(pkgLog, "Printf").Call(
+			jen.Lit("%s %s: %v"),
+			jen.Id("r").Dot("Method"),
+			jen.Id("r").Dot("URL").Dot("Path"),
+			jen.Id("err"),
+		),
+	)
+	gen.Line()
+
+	gen.Id("problem").Dot("Title").Op("=").Qual(pkgHttp, "StatusText").Call(jen.Id("problem").Dot("Status"))
+	gen.Id("w").Dot("Header").Call().Dot("Set").Call(
+		jen.Lit("Content-Type"),
+		jen.Lit("application/problem+json"),
+	)
+	gen.Id("w").Dot("WriteHeader").Call(jen.Id("problem").Dot("Status"))
+	gen.Qual(pkgJson, "NewEncoder").Call(jen.Id("w")).Dot("Encode").Call(jen.Id("problem"))
+}
+
+// renderStatusErrorHandler responds to errors implementing StatusError with
+// their status code. Errors with a server error status are logged.
+func renderStatusErrorHandler(gen *jen.Group) {
+	gen.Var().Id("statusErr").Qual(pkgHttpErr, genStatusError)
+	gen.If(
+		jen.Qual(pkgErrors, "As").Call(
+			jen.Id("err"),
+			jen.Op("&").Id("statusErr"),
+		),
+	).Block(
+		jen.Id("status").Op(":=").Id("statusErr").Dot("StatusCode").Call(),
+		jen.Id("message").Op(":=").Qual(pkgHttp, "StatusText").Call(jen.Id("status")),
+		jen.Line(),
+		jen.If(
+			jen.List(jen.Id("publicErr"), jen.Id("ok")).Op(":=").
+				Id("statusErr").Assert(jen.Qual(pkgHttpErr, genPublicError)),
+			jen.Id("ok"),
+		).Block(
+			jen.Id("message").Op("=").Id("publicErr").Dot("PublicMessage").Call(),
+		),
+		jen.Line(),
+		jen.If(jen.Id("status").Op(">=").Qual(pkgHttp, "StatusInternalServerError")).Block(
+			jen.Qual(pkgLog, "Printf").Call(
+				jen.Lit("%s %s: %v"),
+				jen.Id("r").Dot("Method"),
+				jen.Id("r").Dot("URL").Dot("Path"),
+				jen.Id("err"),
+			),
+		),
+		jen.Line(),
+		jen.Qual(pkgHttp, "Error").Call(
+			jen.Id("w"),
+			jen.Id("message"),
+			jen.Id("status"),
+		),
+		jen.Return(),
+	)
+}
+
+func renderRouterEndpoints(c *ServiceCollection) jen.Code {
+	var funcs jen.Statement
+
+	for _, service := range c.Services {
+		for _, endpoint := range service.Endpoints {
+			funcs.Add(renderEndpointWrapper(endpoint))
+
+			if len(endpoint.ErrorMappings) > 0 {
+				funcs.Add(renderErrorsFunc(endpoint))
+			}
+		}
+	}
+
+	return &funcs
+}
+
+func renderEndpointWrapper(endpoint *Endpoint) jen.Code {
+	// func (s *ServiceRouter) func _handle_<Service>_<Endpoint>(
+	//   w http.ResponseWriter,
+	//   r *http.Request,
+	// ) { ... }
+	return jen.
+		Commentf("%s wraps the endpoint %s#%s.",
+			endpoint.WrapperFunc(),
+			endpoint.Service.Name(),
+			endpoint.FuncName,
+		).Line().
+		Func().
+		Params(genRouterReceiver).
+		Id(endpoint.WrapperFunc()).
+		Params(
+			jen.Id("w").Qual(pkgHttp, "ResponseWriter"),
+			jen.Id("r").Op("*").Qual(pkgHttp, "Request"),
+		).
+		Params(jen.Id("error")).
+		BlockFunc(renderEndpointWrapperBody(endpoint)).
+		Line()
+}
+
+// renderService renders the value of a service, which is a field of the
+// router or, if mounted, a field of its parent.
+func renderService(service *Service) *jen.Statement {
+	if service.Parent == nil {
+		// s.<Service>
+		return jen.Id("s").Dot(service.TypeName)
+	}
+
+	// <Parent>.<Field>
+	return renderService(service.Parent).Dot(service.FieldName)
+}
+
+func renderEndpointWrapperBody(endpoint *Endpoint) func(*jen.Group) {
+	return func(gen *jen.Group) {
+		gen.Defer().Id("r").Dot("Body").Dot("Close").Call()
+
+		if len(endpoint.Produces) > 0 && !customResponseWriter {
+			renderNegotiateResponse(gen, endpoint)
+		}
+
+		for _, param := range endpoint.InputParams {
+			// param0 := <value of "paramName">
+			renderInputParam(gen, param)
+		}
+
+		if len(endpoint.InputParams) > 0 {
+			gen.Line()
+		}
+
+		// s.<Service>.<Endpoint>(<Params>...)
+		callFunc := renderService(endpoint.Service).
+			Dot(endpoint.FuncName).
+			Call(renderInputVars(endpoint.InputVars))
+
+		if !endpoint.ReturnsError && !endpoint.ReturnsValue {
+			gen.Add(callFunc)
+			renderWriteStatus(gen, endpoint)
+			gen.Return().Nil()
+		} else if endpoint.ReturnsError && !endpoint.ReturnsValue {
+			if endpoint.StatusCode == 0 {
+				gen.Return().Add(renderMapErrors(endpoint, callFunc))
+				return
+			}
+
+			gen.If(
+				jen.Id("err").Op(":=").Add(callFunc),
+				jen.Id("err").Op("!=").Nil(),
+			).Block(jen.Return().Add(renderMapErrors(endpoint, jen.Id("err"))))
+			renderWriteStatus(gen, endpoint)
+			gen.Return().Nil()
+		} else {
+			gen.
+				ListFunc(func(gen *jen.Group) {
+					if endpoint.ReturnsValue {
+						gen.Id("val")
+					}
+
+					if endpoint.ReturnsError {
+						gen.Id("err")
+					}
+				}).
+				Op(":=").
+				Add(callFunc)
+
+			if endpoint.ReturnsError {
+				renderIfErrReturn(gen, renderMapErrors(endpoint, jen.Id("err")))
+			}
+
+			if endpoint.Response != nil {
+				renderResponseFields(gen, endpoint.Response)
+			}
+
+			if !endpoint.ReturnsBody() {
+				renderWriteStatus(gen, endpoint)
+				gen.Return().Nil()
+			} else if endpoint.Sequence != 0 {
+				renderSequenceStream(gen, endpoint)
+			} else if endpoint.Stream != 0 {
+				renderStreamResponse(gen, endpoint)
+			} else if customResponseWriter {
+				renderCustomResponse(gen, endpoint)
+			} else {
+				renderEncodeResponse(gen, endpoint)
+			}
+		}
+	}
+}
+
+// needsStatusWriter tests whether the status code of an endpoint is applied
+// to the response written by the custom response writer.
+func needsStatusWriter(endpoint *Endpoint) bool {
+	return endpoint.ReturnsBody() && endpoint.Sequence == 0 && endpoint.Stream == 0 &&
+		(endpoint.StatusCode != 0 || hasStatusField(endpoint.Response))
+}
+
+// renderCustomResponse passes the body to the custom response writer. The
+// status code is written by a wrapping response writer before the body, so
+// that the custom response writer can still set headers.
+func renderCustomResponse(gen *jen.Group, endpoint *Endpoint) {
+	body := renderResponseBody(endpoint)
+
+	if !needsStatusWriter(endpoint) {
+		// return s.WriteResponse(w, r, <body>)
+		gen.Return().Id("s").Dot(genCustomResponse).Call(jen.Id("w"), jen.Id("r"), body)
+		return
+	}
+
+	var status jen.Code = jen.Lit(endpoint.StatusCode)
+
+	if endpoint.Response != nil {
+		for _, field := range endpoint.Response.Fields {
+			if field.ResponseKind != ResponseStatus {
+				continue
+			}
+
+			gen.Id("status").Op(":=").Id("val").Dot(field.Field)
+
+			if endpoint.StatusCode != 0 {
+				gen.If(jen.Id("status").Op("==").Lit(0)).Block(
+					jen.Id("status").Op("=").Lit(endpoint.StatusCode),
+				)
+			}
+
+			status = jen.Id("status")
+			break
+		}
+	}
+
+	gen.Id("sw").Op(":=").Op("&").Id(genStatusWriter).Values(jen.Dict{
+		jen.Id("ResponseWriter"): jen.Id("w"),
+		jen.Id("status"):         status,
+	})
+
+	gen.If(
+		jen.Id("err").Op(":=").Id("s").Dot(genCustomResponse).Call(jen.Id("sw"), jen.Id("r"), body),
+		jen.Id("err").Op("!=").Nil(),
+	).Block(jen.Return().Id("err"))
+
+	gen.Id("sw").Dot("writeStatus").Call()
+	gen.Return().Nil()
+}
+
+// renderStatusResponseWriter renders the response writer applying the status
+// code of an endpoint to the response of the custom response writer.
+func renderStatusResponseWriter(c *ServiceCollection) jen.Code {
+	if !customResponseWriter || !anyEndpoint(c, needsStatusWriter) {
+		return jen.Null()
+	}
+
+	receiver := jen.Id("w").Op("*").Id(genStatusWriter)
+
+	return jen.Add(
+		jen.Comment(genStatusWriter+" writes the status code of an endpoint before").Line().
+			Comment("the body, unless the custom response writer writes one itself.").Line().
+			Type().Id(genStatusWriter).Struct(
+			jen.Qual(pkgHttp, "ResponseWriter"),
+			jen.Id("status").Int(),
+			jen.Id("written").Bool(),
+		).
+			Line().Line(),
+
+		jen.Func().Params(receiver).Id("WriteHeader").Params(jen.Id("status").Int()).Block(
+			jen.Id("w").Dot("written").Op("=").True(),
+			jen.Id("w").Dot("ResponseWriter").Dot("WriteHeader").Call(jen.Id("status")),
+		).
+			Line().Line(),
+
+		jen.Func().Params(receiver).Id("Write").
+			Params(jen.Id("p").Index().Byte()).
+			Params(jen.Int(), jen.Error()).
+			Block(
+				jen.Id("w").Dot("writeStatus").Call(),
+				jen.Return().Id("w").Dot("ResponseWriter").Dot("Write").Call(jen.Id("p")),
+			).
+			Line().Line(),
+
+		jen.Comment("writeStatus writes the status code, if no status code was written yet.").Line().
+			Func().Params(receiver).Id("writeStatus").Params().Block(
+			jen.If(jen.Op("!").Id("w").Dot("written").Op("&&").Id("w").Dot("status").Op("!=").Lit(0)).Block(
+				jen.Id("w").Dot("WriteHeader").Call(jen.Id("w").Dot("status")),
+			),
+		),
+	)
+}
+
+// renderWriteStatus writes the status code of the response. A status code
+// returned in a response struct takes precedence over the declared one.
+func renderWriteStatus(gen *jen.Group, endpoint *Endpoint) {
+	var declared jen.Code
+
+	if endpoint.StatusCode != 0 {
+		declared = jen.Id("w").Dot("WriteHeader").Call(jen.Lit(endpoint.StatusCode))
+	}
+
+	if endpoint.Response != nil {
+		for _, field := range endpoint.Response.Fields {
+			if field.ResponseKind != ResponseStatus {
+				continue
+			}
+
+			status := jen.Id("val").Dot(field.Field)
+			stmt := gen.If(status.Clone().Op("!=").Lit(0)).Block(
+				jen.Id("w").Dot("WriteHeader").Call(status),
+			)
+
+			if declared != nil {
+				stmt.Else().Block(declared)
+			}
+
+			return
+		}
+	}
+
+	if declared != nil {
+		gen.Add(declared)
+	}
+}
+
+// renderResponseFields writes the headers and cookies of a response struct.
+func renderResponseFields(gen *jen.Group, response *ResponseStruct) {
+	header := jen.Id("w").Dot("Header").Call()
+
+	for _, field := range response.Fields {
+		value := jen.Id("val").Dot(field.Field)
+
+		switch field.ResponseKind {
+		case ResponseHeader:
+			if field.Slice {
+				gen.For(jen.List(jen.Id("_"), jen.Id("value")).Op(":=").Range().Add(value)).Block(
+					header.Clone().Dot("Add").Call(
+						jen.Lit(field.Name),
+						renderTextFormat(jen.Id("value"), field.TextFormat),
+					),
+				)
+				continue
+			}
+
+			set := header.Clone().Dot("Set").Call(
+				jen.Lit(field.Name),
+				renderTextFormat(renderDeref(value, field.Pointer, field.TextFormat), field.TextFormat),
+			)
+
+			switch {
+			case field.Pointer:
+				gen.If(value.Clone().Op("!=").Nil()).Block(set)
+			case field.Format == FormatString:
+				gen.If(value.Clone().Op("!=").Lit("")).Block(set)
+			default:
+				gen.Add(set)
+			}
+
+		case ResponseHeaders:
+			gen.For(jen.List(jen.Id("key"), jen.Id("values")).Op(":=").Range().Add(value)).Block(
+				jen.For(jen.List(jen.Id("_"), jen.Id("value")).Op(":=").Range().Id("values")).Block(
+					header.Clone().Dot("Add").Call(jen.Id("key"), jen.Id("value")),
+				),
+			)
+
+		case ResponseCookie:
+			switch {
+			case field.Slice && field.Pointer:
+				gen.For(jen.List(jen.Id("_"), jen.Id("cookie")).Op(":=").Range().Add(value)).Block(
+					jen.Qual(pkgHttp, "SetCookie").Call(jen.Id("w"), jen.Id("cookie")),
+				)
+			case field.Slice:
+				gen.For(jen.Id("k").Op(":=").Range().Add(value)).Block(
+					jen.Qual(pkgHttp, "SetCookie").Call(
+						jen.Id("w"),
+						jen.Op("&").Add(value.Clone()).Index(jen.Id("k")),
+					),
+				)
+			case field.Pointer:
+				gen.Qual(pkgHttp, "SetCookie").Call(jen.Id("w"), value)
+			default:
+				gen.Qual(pkgHttp, "SetCookie").Call(jen.Id("w"), jen.Op("&").Add(value))
+			}
+		}
+	}
+}
+
+// renderResponseBody returns the value, that is encoded as the body of the
+// response.
+func renderResponseBody(endpoint *Endpoint) *jen.Statement {
+	if endpoint.Response != nil {
+		return jen.Id("val").Dot(endpoint.Response.Body)
+	}
+
+	return jen.Id("val")
+}
+
+var mediaTypeHeaders = map[string]string{
+	MediaJson: "application/json",
+	MediaXml:  "application/xml",
+	MediaText: "text/plain; charset=utf-8",
+	MediaCsv:  "text/csv; charset=utf-8",
+}
+
+func renderNegotiateResponse(gen *jen.Group, endpoint *Endpoint) {
+	offers := []jen.Code{jen.Id("r")}
+	for _, mediaType := range endpoint.Produces {
+		offers = append(offers, jen.Lit(mediaType))
+	}
+
+	gen.Line()
+	gen.Comment("Negotiate the media type of the response.")
+	gen.Id("responseType").Op(":=").Id(genNegotiate).Call(offers...)
+	gen.If(jen.Id("responseType").Op("==").Lit("")).Block(
+		jen.Return().Qual(pkgHttpErr, genErrAcceptable),
+	)
+	gen.Line()
+}
+
+// renderEncodeResponse encodes the returned value according to the
+// negotiated media type.
+func renderEncodeResponse(gen *jen.Group, endpoint *Endpoint) {
+	body := renderResponseBody(endpoint)
+
+	gen.Switch(jen.Id("responseType")).BlockFunc(func(g *jen.Group) {
+		for _, mediaType := range endpoint.Produces {
+			g.Case(jen.Lit(mediaType)).BlockFunc(func(g *jen.Group) {
+				g.Id("w").Dot("Header").Call().Dot("Set").Call(
+					jen.Lit("Content-Type"),
+					jen.Lit(mediaTypeHeaders[mediaType]),
+				)
+				renderWriteStatus(g, endpoint)
+
+				switch mediaType {
+				case MediaJson:
+					g.Return().
+						Qual(pkgJson, "NewEncoder").Call(jen.Id("w")).
+						Dot("Encode").Call(body)
+
+				case MediaXml:
+					g.Return().
+						Qual(pkgXml, "NewEncoder").Call(jen.Id("w")).
+						Dot("Encode").Call(body)
+
+				case MediaText:
+					g.List(jen.Id("_"), jen.Id("err")).Op(":=").Qual(pkgIo, "WriteString").Call(
+						jen.Id("w"),
+						renderTextFormat(body, endpoint.TextFormat),
+					)
+					g.Return().Id("err")
+
+				case MediaCsv:
+					renderEncodeCsv(g, body, endpoint.CsvColumns)
+				}
+			})
+		}
+
+		g.Default().Block(
+			jen.Return().Qual(pkgHttpErr, genErrAcceptable),
+		)
+	})
+}
+
+// renderStreamResponse copies a returned stream to the response. Seekable
+// streams are served using http.ServeContent, which supports range and
+// conditional requests, unless a different status code is written.
+func renderStreamResponse(gen *jen.Group, endpoint *Endpoint) {
+	var (
+		body  = renderResponseBody(endpoint)
+		serve = endpoint.StatusCode == 0 && !hasStatusField(endpoint.Response)
+	)
+
+	gen.If(body.Clone().Op("==").Nil()).BlockFunc(func(g *jen.Group) {
+		renderWriteStatus(g, endpoint)
+		g.Return().Nil()
+	})
+	gen.Line()
+
+	if endpoint.Stream != StreamReader {
+		gen.Defer().Add(body.Clone()).Dot("Close").Call()
+	}
+
+	if endpoint.ContentType != "" {
+		gen.Id("w").Dot("Header").Call().Dot("Set").Call(
+			jen.Lit("Content-Type"),
+			jen.Lit(endpoint.ContentType),
+		)
+	}
+
+	if serve {
+		serveContent := func(name, modtime, content jen.Code) jen.Code {
+			return jen.Qual(pkgHttp, "ServeContent").Call(jen.Id("w"), jen.Id("r"), name, modtime, content)
+		}
+
+		stat := []jen.Code{
+			jen.List(jen.Id("info"), jen.Id("err")).Op(":=").Add(body.Clone()).Dot("Stat").Call(),
+			jen.If(jen.Id("err").Op("!=").Nil()).Block(jen.Return().Id("err")),
+			jen.Line(),
+		}
+
+		switch endpoint.Stream {
+		case StreamOsFile:
+			for _, code := range stat {
+				gen.Add(code)
+			}
+
+			gen.Add(serveContent(
+				jen.Id("info").Dot("Name").Call(),
+				jen.Id("info").Dot("ModTime").Call(),
+				body.Clone(),
+			))
+			gen.Return().Nil()
+			return
+
+		case StreamFile:
+			gen.If(
+				jen.List(jen.Id("content"), jen.Id("ok")).Op(":=").Add(body.Clone()).
+					Assert(jen.Qual(pkgIo, "ReadSeeker")),
+				jen.Id("ok"),
+			).Block(append(stat,
+				serveContent(
+					jen.Id("info").Dot("Name").Call(),
+					jen.Id("info").Dot("ModTime").Call(),
+					jen.Id("content"),
+				),
+				jen.Return().Nil(),
+			)...)
+
+		default:
+			gen.If(
+				jen.List(jen.Id("content"), jen.Id("ok")).Op(":=").Add(body.Clone()).
+					Assert(jen.Qual(pkgIo, "ReadSeeker")),
+				jen.Id("ok"),
+			).Block(
+				serveContent(jen.Lit(""), jen.Qual(pkgTime, "Time").Values(), jen.Id("content")),
+				jen.Return().Nil(),
+			)
+		}
+
+		gen.Line()
+	}
+
+	assign := ":="
+	if endpoint.ReturnsError {
+		assign = "="
+	}
+
+	renderWriteStatus(gen, endpoint)
+	gen.List(jen.Id("_"), jen.Id("err")).Op(assign).Qual(pkgIo, "Copy").Call(jen.Id("w"), body)
+	gen.Return().Id("err")
+}
+
+func hasStatusField(response *ResponseStruct) bool {
+	if response != nil {
+		for _, field := range response.Fields {
+			if field.ResponseKind == ResponseStatus {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ndjsonFlushLines is the number of lines of json written between flushes.
+const ndjsonFlushLines = 100
+
+// renderSequenceStream writes every value of a sequence either as an event or
+// as a line of json, until the request is cancelled. Lines are flushed
+// periodically and whenever a channel has no more values buffered.
+func renderSequenceStream(gen *jen.Group, endpoint *Endpoint) {
+	var (
+		body  = renderResponseBody(endpoint)
+		write = jen.Id("encoder").Dot("Encode")
+		flush = jen.Null()
+	)
+
+	if endpoint.Stream == StreamEvents {
+		write = jen.Id(genWriteEvent).Call(jen.Id("w"), jen.Id("value"))
+	} else {
+		write = write.Call(jen.Id("value"))
+	}
+
+	write = jen.If(
+		jen.Id("err").Op(":=").Add(write),
+		jen.Id("err").Op("!=").Nil(),
+	).Block(jen.Return().Id("err"))
+
+	if endpoint.Sequence != SequenceSlice {
+		gen.If(body.Clone().Op("==").Nil()).BlockFunc(func(g *jen.Group) {
+			renderWriteStatus(g, endpoint)
+			g.Return().Nil()
+		})
+		gen.Line()
+	}
+
+	if endpoint.Stream == StreamEvents {
+		gen.Id("w").Dot("Header").Call().Dot("Set").Call(jen.Lit("Content-Type"), jen.Lit(MediaEvents))
+		gen.Id("w").Dot("Header").Call().Dot("Set").Call(jen.Lit("Cache-Control"), jen.Lit("no-cache"))
+	} else {
+		gen.Id("w").Dot("Header").Call().Dot("Set").Call(jen.Lit("Content-Type"), jen.Lit(MediaNdjson))
+	}
+
+	renderWriteStatus(gen, endpoint)
+	gen.Add(renderFlush())
+	gen.Line()
+
+	if endpoint.Stream == StreamNdjson {
+		gen.Id("encoder").Op(":=").Qual(pkgJson, "NewEncoder").Call(jen.Id("w"))
+
+		if endpoint.Sequence == SequenceChan {
+			flush = jen.If(jen.Len(body.Clone()).Op("==").Lit(0)).Block(renderFlush())
+		} else {
+			gen.Id("lines").Op(":=").Lit(0)
+			flush = jen.If(
+				jen.Id("lines").Op("++"),
+				jen.Id("lines").Op("%").Lit(ndjsonFlushLines).Op("==").Lit(0),
+			).Block(renderFlush())
+		}
+
+		gen.Line()
+	}
+
+	switch endpoint.Sequence {
+	case SequenceSlice:
+		gen.For(jen.List(jen.Id("_"), jen.Id("value")).Op(":=").Range().Add(body)).Block(
+			write,
+			flush,
+		)
+		gen.Return().Nil()
+
+	case SequenceIterator:
+		gen.For(jen.Id("value").Op(":=").Range().Add(body)).Block(
+			write,
+			flush,
+			jen.If(jen.Id("r").Dot("Context").Call().Dot("Err").Call().Op("!=").Nil()).Block(
+				jen.Break(),
+			),
+		)
+		gen.Return().Nil()
+
+	case SequenceChan:
+		gen.For().Block(
+			jen.Select().Block(
+				jen.Case(jen.Op("<-").Id("r").Dot("Context").Call().Dot("Done").Call()).Block(
+					jen.Return().Nil(),
+				),
+				jen.Case(jen.List(jen.Id("value"), jen.Id("ok")).Op(":=").Op("<-").Add(body)).Block(
+					jen.If(jen.Op("!").Id("ok")).Block(jen.Return().Nil()),
+					write,
+					flush,
+				),
+			),
+		)
+	}
+}
+
+// renderEncodeCsv writes a header record with the column names followed by
+// a record for every row.
+func renderEncodeCsv(gen *jen.Group, rows jen.Code, columns []CsvColumn) {
+	var names []jen.Code
+	for _, column := range columns {
+		names = append(names, jen.Lit(column.Name))
+	}
+
+	gen.Id("records").Op(":=").Qual(pkgCsv, "NewWriter").Call(jen.Id("w"))
+	gen.If(
+		jen.Id("err").Op(":=").Id("records").Dot("Write").Call(jen.Index().String().Values(names...)),
+		jen.Id("err").Op("!=").Nil(),
+	).Block(jen.Return().Id("err"))
+
+	gen.For(jen.List(jen.Id("_"), jen.Id("row")).Op(":=").Range().Add(rows)).
+		BlockFunc(func(g *jen.Group) {
+			g.Id("record").Op(":=").Make(jen.Index().String(), jen.Lit(len(columns)))
+
+			for k, column := range columns {
+				var (
+					field  = jen.Id("row").Dot(column.Field)
+					target = jen.Id("record").Index(jen.Lit(k))
+				)
+
+				if column.Pointer {
+					value := renderDeref(field.Clone(), true, column.TextFormat)
+
+					g.If(field.Clone().Op("!=").Nil()).Block(
+						target.Op("=").Add(renderTextFormat(value, column.TextFormat)),
+					)
+				} else {
+					g.Add(target).Op("=").Add(renderTextFormat(field, column.TextFormat))
+				}
+			}
+
+			g.If(
+				jen.Id("err").Op(":=").Id("records").Dot("Write").Call(jen.Id("record")),
+				jen.Id("err").Op("!=").Nil(),
+			).Block(jen.Return().Id("err"))
+		})
+
+	gen.Id("records").Dot("Flush").Call()
+	gen.Return().Id("records").Dot("Error").Call()
+}
+
+// renderDeref dereferences a pointer to a value, that is formatted as text.
+// Methods are called on pointers as well.
+func renderDeref(value *jen.Statement, pointer bool, format TextFormat) *jen.Statement {
+	switch {
+	case !pointer, format.Format == FormatTime, format.Format == FormatHttpTime,
+		format.Format == FormatStringer:
+		return value
+	}
+
+	return jen.Op("*").Add(value)
+}
+
+func renderTextFormat(value jen.Code, format TextFormat) jen.Code {
+	switch format.Format {
+	case FormatBool:
+		return jen.Qual(pkgStrconv, "FormatBool").Call(jen.Bool().Parens(value))
+
+	case FormatInt:
+		return jen.Qual(pkgStrconv, "FormatInt").Call(jen.Int64().Parens(value), jen.Lit(10))
+
+	case FormatUint:
+		return jen.Qual(pkgStrconv, "FormatUint").Call(jen.Uint64().Parens(value), jen.Lit(10))
+
+	case FormatFloat:
+		return jen.Qual(pkgStrconv, "FormatFloat").Call(
+			jen.Float64().Parens(value),
+			jen.LitRune('g'),
+			jen.Lit(-1),
+			jen.Lit(format.BitSize),
+		)
+
+	case FormatTime:
+		return jen.Add(value).Dot("Format").Call(jen.Qual(pkgTime, "RFC3339"))
+
+	case FormatHttpTime:
+		return jen.Add(value).Dot("UTC").Call().Dot("Format").Call(jen.Qual(pkgHttp, "TimeFormat"))
+
+	case FormatStringer:
+		return jen.Add(value).Dot(mString).Call()
+	}
+
+	return jen.String().Parens(value)
+}
+
+// renderMapErrors passes an error returned by an endpoint through its error
+// mappings, if there are any.
+func renderMapErrors(endpoint *Endpoint, err jen.Code) jen.Code {
+	if len(endpoint.ErrorMappings) == 0 {
+		return err
+	}
+
+	return jen.Id("s").Dot(endpoint.ErrorsFunc()).Call(err)
+}
+
+// renderErrorsFunc renders a function, that maps the errors of an endpoint to
+// errors with a status code.
+func renderErrorsFunc(endpoint *Endpoint) jen.Code {
+	return jen.
+		Commentf("%s maps the errors of the endpoint %s#%s.",
+			endpoint.ErrorsFunc(),
+			endpoint.Service.Name(),
+			endpoint.FuncName,
+		).Line().
+		Func().
+		Params(genRouterReceiver).
+		Id(endpoint.ErrorsFunc()).
+		Params(jen.Id("err").Error()).
+		Error().
+		Block(
+			jen.Switch().BlockFunc(func(g *jen.Group) {
+				for _, mapping := range endpoint.ErrorMappings {
+					g.Case(jen.Qual(pkgErrors, "Is").Call(jen.Id("err"), renderTypeName(mapping.VarPackage, mapping.VarName))).Block(
+						jen.Return().Op("&").Qual(pkgHttpErr, genHttpError).Values(jen.Dict{
+							jen.Id("Status"):  jen.Lit(mapping.StatusCode),
+							jen.Id("Message"): jen.Lit(http.StatusText(mapping.StatusCode)),
+							jen.Id("Err"):     jen.Id("err"),
+						}),
+					)
+				}
+			}),
+			jen.Line(),
+			jen.Return().Id("err"),
+		).
+		Line()
+}
+
+func renderInputParam(gen *jen.Group, param InputParam) {
+	switch param.ParamKind {
+	case KindStringParam:
+		renderStringParam(gen, param)
+
+	case KindQueryParam:
+		renderQueryParam(gen, param)
+
+	case KindHeaderParam:
+		renderHeaderParam(gen, param)
+
+	case KindCookieParam:
+		renderCookieParam(gen, param)
+
+	case KindFormParam:
+		renderFormParam(gen, param)
+
+	case KindStructParam:
+		renderStructParam(gen, param)
+
+	case KindConvertParam:
+		renderConvertParam(gen, param)
+
+	case KindSliceParam:
+		renderSliceParam(gen, param)
+
+	case KindPayloadParam:
+		renderPayloadParam(gen, param)
+
+	case KindResolveParam:
+		renderResolverParam(gen, param)
+	}
+
+	gen.Line()
+}
+
+func renderStringParam(gen *jen.Group, param InputParam) {
+	gen.Commentf("Extract url parameter %s.", param.ParamKey)
+	gen.Id(param.VarName).Op(":=").Add(routerBackends[routerName].PathParam(param))
+}
+
+func renderQueryParam(gen *jen.Group, param InputParam) {
+	gen.Commentf("Extract query parameter %s.", param.ParamKey)
+	gen.Id(param.VarName).Op(":=").Id("r").Dot("URL").Dot("Query").Call().
+		Dot("Get").Call(jen.Lit(param.ParamKey))
+
+	if needsPresence(param) {
+		gen.List(jen.Id("_"), jen.Id(param.VarName+"ok")).Op(":=").
+			Id("r").Dot("URL").Dot("Query").Call().Index(jen.Lit(param.ParamKey))
+
+		renderAbsentParam(gen, param)
+	}
+}
+
+func renderHeaderParam(gen *jen.Group, param InputParam) {
+	gen.Commentf("Extract header %s.", param.ParamKey)
+	gen.Id(param.VarName).Op(":=").Id("r").Dot("Header").
+		Dot("Get").Call(jen.Lit(param.ParamKey))
+
+	if needsPresence(param) {
+		gen.List(jen.Id("_"), jen.Id(param.VarName+"ok")).Op(":=").
+			Id("r").Dot("Header").Index(jen.Lit(http.CanonicalHeaderKey(param.ParamKey)))
+
+		renderAbsentParam(gen, param)
+	}
+}
+
+func renderCookieParam(gen *jen.Group, param InputParam) {
+	cookieName := param.VarName + "cookie"
+
+	gen.Commentf("Extract cookie %s.", param.ParamKey)
+	gen.Var().Id(param.VarName).String()
+
+	if needsPresence(param) {
+		gen.List(jen.Id(cookieName), jen.Id("err")).Op(":=").
+			Id("r").Dot("Cookie").Call(jen.Lit(param.ParamKey))
+		gen.Id(param.VarName + "ok").Op(":=").Id("err").Op("==").Nil()
+		gen.If(jen.Id(param.VarName + "ok")).Block(
+			jen.Id(param.VarName).Op("=").Id(cookieName).Dot("Value"),
+		)
+
+		renderAbsentParam(gen, param)
+	} else {
+		gen.If(
+			jen.List(jen.Id(cookieName), jen.Id("err")).Op(":=").
+				Id("r").Dot("Cookie").Call(jen.Lit(param.ParamKey)),
+			jen.Id("err").Op("==").Nil(),
+		).Block(
+			jen.Id(param.VarName).Op("=").Id(cookieName).Dot("Value"),
+		)
+	}
+}
+
+func renderFormParam(gen *jen.Group, param InputParam) {
+	gen.Commentf("Extract form value %s.", param.ParamKey)
+	gen.Id(param.VarName).Op(":=").Id("r").Dot("FormValue").Call(jen.Lit(param.ParamKey))
+
+	if needsPresence(param) {
+		gen.List(jen.Id("_"), jen.Id(param.VarName+"ok")).Op(":=").
+			Id("r").Dot("Form").Index(jen.Lit(param.ParamKey))
+
+		renderAbsentParam(gen, param)
+	}
+}
+
+// needsPresence tests whether the presence of an extracted value is checked.
+// The result of the check is stored in a variable suffixed with "ok".
+func needsPresence(param InputParam) bool {
+	return param.Optional || param.Required || param.Default != ""
+}
+
+func renderAbsentParam(gen *jen.Group, param InputParam) {
+	notOk := jen.Op("!").Id(param.VarName + "ok")
+
+	if param.Default != "" {
+		gen.If(notOk).Block(
+			jen.Id(param.VarName).Op("=").Lit(param.Default),
+		)
+	} else if param.Required {
+		gen.If(notOk).Block(
+			jen.Return().Add(renderMissingParam(param)),
+		)
+	}
+}
+
+func renderMissingParam(param InputParam) jen.Code {
+	return renderBindingErrorValue(BindingSource(param.ParamKind), param.ParamKey,
+		jen.Qual(pkgHttpErr, genErrMissing))
+}
+
+// renderConversionError returns a binding error for a failed conversion of
+// the value extracted for a param.
+func renderConversionError(param InputParam) jen.Code {
+	return renderBindingErrorValue(BindingSource(param.BindingKind), param.ParamKey,
+		jen.Id("err"))
+}
+
+func renderBindingErrorValue(source, name string, err jen.Code) jen.Code {
+	values := jen.Dict{
+		jen.Id("Source"): jen.Lit(source),
+		jen.Id("Err"):    err,
+	}
+
+	if name != "" {
+		values[jen.Id("Name")] = jen.Lit(name)
+	}
+
+	return jen.Op("&").Qual(pkgHttpErr, genBindingError).Values(values)
+}
+
+func renderConvertParam(gen *jen.Group, param InputParam) {
+	stringVar := param.InputVars[0]
+
+	if !param.Optional {
+		gen.Commentf("Convert %s to %s.", stringVar.VarName, param.TypeName)
+		renderConversion(gen, param.VarName, jen.Id(stringVar.VarName),
+			param.TypeName, param.TypePackage, renderConversionError(param))
+		return
+	}
+
+	valueName := param.VarName + "value"
+
+	gen.Commentf("Convert %s to *%s, if present.", stringVar.VarName, param.TypeName)
+	gen.Var().Id(param.VarName).Op("*").Add(renderTypeName(param.TypePackage, param.TypeName))
+	gen.If(jen.Id(stringVar.VarName + "ok")).BlockFunc(func(g *jen.Group) {
+		renderConversion(g, valueName, jen.Id(stringVar.VarName),
+			param.TypeName, param.TypePackage, renderConversionError(param))
+		g.Id(param.VarName).Op("=").Op("&").Id(valueName)
+	})
+}
+
+func renderSliceParam(gen *jen.Group, param InputParam) {
+	valuesName := param.VarName + "values"
+
+	if len(param.InputVars) > 0 {
+		stringVar := param.InputVars[0]
+
+		gen.Commentf("Split %s into []%s.", stringVar.VarName, param.TypeName)
+		gen.Var().Id(valuesName).Index().String()
+		gen.If(jen.Id(stringVar.VarName).Op("!=").Lit("")).Block(
+			jen.Id(valuesName).Op("=").Qual(pkgStrings, "Split").Call(
+				jen.Id(stringVar.VarName),
+				jen.Lit(","),
+			),
+		)
+	} else {
+		gen.Commentf("Collect query parameter %s into []%s.", param.ParamKey, param.TypeName)
+		gen.Id(valuesName).Op(":=").Id("r").Dot("URL").Dot("Query").Call().
+			Index(jen.Lit(param.ParamKey))
+
+		if param.Default != "" {
+			gen.If(jen.Len(jen.Id(valuesName)).Op("==").Lit(0)).Block(
+				jen.Id(valuesName).Op("=").Index().String().ValuesFunc(func(g *jen.Group) {
+					for _, value := range strings.Split(param.Default, ",") {
+						g.Lit(value)
+					}
+				}),
+			)
+		}
+	}
+
+	if param.TypeName == "string" && param.TypePackage == "" {
+		gen.Id(param.VarName).Op(":=").Id(valuesName)
+		return
+	}
+
+	gen.Id(param.VarName).Op(":=").Make(
+		renderParamType(param),
+		jen.Lit(0),
+		jen.Len(jen.Id(valuesName)),
+	)
+	gen.For(jen.List(jen.Id("_"), jen.Id("value")).Op(":=").Range().Id(valuesName)).
+		BlockFunc(func(g *jen.Group) {
+			renderConversion(g, "elem", jen.Id("value"), param.TypeName, param.TypePackage,
+				renderConversionError(param))
+			g.Id(param.VarName).Op("=").Append(jen.Id(param.VarName), jen.Id("elem"))
+		})
+}
+
+func renderStructParam(gen *jen.Group, param InputParam) {
+	gen.Commentf("Bind fields of %s.", param.TypeName)
+	gen.Var().Id(param.VarName).Add(renderTypeName(param.TypePackage, param.TypeName))
+
+	for k, inputVar := range param.InputVars {
+		gen.Id(param.VarName).Dot(param.Fields[k]).Op("=").Add(renderInputVar(inputVar))
+	}
+
+	renderValidations(gen, param)
+}
+
+func renderValidations(gen *jen.Group, param InputParam) {
+	if len(param.Validations) == 0 && !param.Validates {
+		return
+	}
+
+	gen.Line()
+	gen.Commentf("Validate %s.", param.VarName)
+
+	for _, v := range param.Validations {
+		var (
+			field   = jen.Id(param.VarName).Dot(v.Field)
+			value   = field.Clone()
+			verb    = "be"
+			message string
+			cond    *jen.Statement
+		)
+
+		if v.Pointer {
+			value = jen.Op("*").Add(value)
+		}
+
+		if v.Target == ValidatedLength {
+			value = jen.Len(value)
+			verb = "have a length of"
+		}
+
+		switch v.Rule {
+		case ValidateRequired:
+			message = "is required"
+
+			switch {
+			case v.Pointer:
+				cond = field.Clone().Op("==").Nil()
+			case v.Target == ValidatedBool:
+				cond = jen.Op("!").Add(value)
+			case v.Target == ValidatedZeroer:
+				cond = value.Clone().Dot(mIsZero).Call()
+			default:
+				cond = value.Clone().Op("==").Lit(0)
+			}
+
+		case ValidateMin:
+			message = fmt.Sprintf("must %s at least %s", verb, v.Arg)
+			cond = value.Clone().Op("<").Lit(v.Bound)
+
+		case ValidateMax:
+			message = fmt.Sprintf("must %s at most %s", verb, v.Arg)
+			cond = value.Clone().Op(">").Lit(v.Bound)
+		}
+
+		if v.Pointer && v.Rule != ValidateRequired {
+			cond = field.Clone().Op("!=").Nil().Op("&&").Add(cond)
+		}
+
+		// The message of a rule is public, so it is sent to the client.
+		gen.If(cond).Block(
+			jen.Return().Add(renderBindingErrorValue(v.Source, v.Name,
+				jen.Qual(pkgHttpErr, "BadRequest").Call(jen.Lit(message)))),
+		)
+	}
+
+	if param.Validates {
+		gen.If(
+			jen.Id("err").Op(":=").Id(param.VarName).Dot(mValidate).Call(),
+			jen.Id("err").Op("!=").Nil(),
+		).Block(
+			jen.Return().Add(renderBindingErrorValue(BindingSource(param.ParamKind), "", jen.Id("err"))),
+		)
+	}
+}
+
+// renderConversion declares a variable with the given name holding the
+// converted string value. If the conversion fails, the given error value is
+// returned.
+func renderConversion(gen *jen.Group, varName string, value jen.Code, typeName, typePackage string, errValue jen.Code) {
+	if typePackage != "" {
+		renderTypeConversion(gen, varName, value, typeName, typePackage, errValue)
+		return
+	}
+
+	switch typeName {
+	case "int", "int8", "int16", "int32", "int64", "rune",
+		"uint", "uint8", "uint16", "uint32", "uint64", "byte":
+
+		var (
+			bitSize     = bitSizes[typeName]
+			unsizedType = strings.TrimRight(typeName, "123468")
+			tempName    = varName + "b64"
+		)
+
+		switch typeName {
+		case "byte":
+			unsizedType = "uint"
+		case "rune":
+			unsizedType = "int"
+		}
+
+		gen.List(jen.Id(tempName), jen.Id("err")).
+			Op(":=").
+			Qual(pkgStrconv, "Parse"+strings.Title(unsizedType)).
+			Call(value, jen.Lit(10), jen.Lit(bitSize))
+
+		renderIfErrReturn(gen, errValue)
+		gen.Id(varName).Op(":=").Id(typeName).Call(jen.Id(tempName))
+
+	case "float32", "float64":
+		tempName := varName + "f64"
+
+		gen.List(jen.Id(tempName), jen.Id("err")).
+			Op(":=").
+			Qual(pkgStrconv, "ParseFloat").
+			Call(value, jen.Lit(bitSizes[typeName]))
+
+		renderIfErrReturn(gen, errValue)
+		gen.Id(varName).Op(":=").Id(typeName).Call(jen.Id(tempName))
+
+	case "bool":
+		gen.List(jen.Id(varName), jen.Id("err")).
+			Op(":=").
+			Qual(pkgStrconv, "ParseBool").
+			Call(value)
+
+		renderIfErrReturn(gen, errValue)
+
+	case "[]byte":
+		gen.Id(varName).Op(":=").Index().Byte().Parens(value)
+
+	case "string":
+		gen.Id(varName).Op(":=").Add(value)
+	}
+}
+
+func renderTypeConversion(gen *jen.Group, varName string, value jen.Code, typeName, typePackage string, errValue jen.Code) {
+	switch {
+	case typePackage == pkgTime && typeName == "Time":
+		gen.List(jen.Id(varName), jen.Id("err")).
+			Op(":=").
+			Qual(pkgTime, "Parse").
+			Call(jen.Qual(pkgTime, "RFC3339"), value)
+
+		renderIfErrReturn(gen, errValue)
+
+	case typePackage == pkgTime && typeName == "Duration":
+		gen.List(jen.Id(varName), jen.Id("err")).
+			Op(":=").
+			Qual(pkgTime, "ParseDuration").
+			Call(value)
+
+		renderIfErrReturn(gen, errValue)
+
+	default:
+		gen.Var().Id(varName).Add(renderTypeName(typePackage, typeName))
+		gen.If(
+			jen.Id("err").Op(":=").Id(varName).Dot(mUnmarshalText).Call(
+				jen.Index().Byte().Parens(value),
+			),
+			jen.Id("err").Op("!=").Nil(),
+		).Block(jen.Return().Add(errValue))
+	}
+}
+
+var bitSizes = map[string]int{
+	"int": 0, "int8": 8, "int16": 16, "int32": 32, "int64": 64, "rune": 32,
+	"uint": 0, "uint8": 8, "uint16": 16, "uint32": 32, "uint64": 64, "byte": 8,
+	"float32": 32, "float64": 64,
+}
+
+func renderPayloadParam(gen *jen.Group, param InputParam) {
+	gen.Var().Id(param.VarName).Add(renderParamType(param))
+
+	if customRequestReader {
+		if param.Restricted {
+			renderConsumesCheck(gen, param)
+		}
+
+		renderDecoding(gen, param, jen.Id("s").Dot(genCustomRequest).Call(
+			jen.Id("r"),
+			jen.Op("&").Id(param.VarName),
+		))
+	} else {
+		renderMediaTypeSwitch(gen, param)
+	}
+
+	renderValidations(gen, param)
+}
+
+// renderConsumesCheck rejects requests, whose content type is not declared
+// as consumed, before the payload is passed to the custom request reader. A
+// request without content type is accepted like by the builtin decoders.
+func renderConsumesCheck(gen *jen.Group, param InputParam) {
+	mediaTypes := []jen.Code{jen.Lit("")}
+	for _, mediaType := range param.Consumes {
+		mediaTypes = append(mediaTypes, jen.Lit(mediaType))
+	}
+
+	gen.Comment("Reject content types, that are not consumed.")
+	gen.List(jen.Id("mediaType"), jen.Id("_"), jen.Id("_")).Op(":=").
+		Qual(pkgMime, "ParseMediaType").Call(
+		jen.Id("r").Dot("Header").Dot("Get").Call(jen.Lit("Content-Type")),
+	)
+
+	gen.Switch(jen.Id("mediaType")).Block(
+		jen.Case(mediaTypes...),
+		jen.Default().Block(
+			jen.Return().Qual(pkgHttpErr, genErrUnsupported),
+		),
+	)
+}
+
+// renderMediaTypeSwitch decodes the payload depending on the content type of
+// the request. A request without content type is decoded using the first
+// consumed media type.
+func renderMediaTypeSwitch(gen *jen.Group, param InputParam) {
+	var (
+		decoders   []string
+		mediaTypes = make(map[string][]jen.Code)
+	)
+
+	for k, mediaType := range param.Consumes {
+		decoder := consumableMediaTypes[mediaType]
+
+		// Both kinds of forms are parsed by the same decoder.
+		if decoder == MediaMultipart {
+			decoder = MediaForm
+		}
+
+		if _, ok := mediaTypes[decoder]; !ok {
+			decoders = append(decoders, decoder)
+		}
+
+		if k == 0 {
+			mediaTypes[decoder] = append(mediaTypes[decoder], jen.Lit(""))
+		}
+
+		mediaTypes[decoder] = append(mediaTypes[decoder], jen.Lit(mediaType))
+	}
+
+	gen.Commentf("Decode %s according to the content type.", param.VarName)
+	gen.List(jen.Id("mediaType"), jen.Id("_"), jen.Id("_")).Op(":=").
+		Qual(pkgMime, "ParseMediaType").Call(
+		jen.Id("r").Dot("Header").Dot("Get").Call(jen.Lit("Content-Type")),
+	)
+
+	gen.Switch(jen.Id("mediaType")).BlockFunc(func(g *jen.Group) {
+		for _, decoder := range decoders {
+			g.Case(mediaTypes[decoder]...).BlockFunc(func(g *jen.Group) {
+				switch decoder {
+				case MediaJson:
+					renderDecoding(g, param, jen.
+						Qual(pkgJson, "NewDecoder").Call(jen.Id("r").Dot("Body")).
+						Dot("Decode").Call(jen.Op("&").Id(param.VarName)))
+
+				case MediaXml:
+					renderDecoding(g, param, jen.
+						Qual(pkgXml, "NewDecoder").Call(jen.Id("r").Dot("Body")).
+						Dot("Decode").Call(jen.Op("&").Id(param.VarName)))
+
+				case MediaForm:
+					renderFormDecoding(g, param)
+				}
+			})
+		}
+
+		g.Default().Block(
+			jen.Return().Qual(pkgHttpErr, genErrUnsupported),
+		)
+	})
+}
+
+func renderDecoding(gen *jen.Group, param InputParam, decoderCall jen.Code) {
+	gen.If(
+		jen.Id("err").Op(":=").Add(decoderCall),
+		jen.Id("err").Op("!=").Nil(),
+	).Block(
+		jen.Return().Add(renderBindingErrorValue(BindingSource(param.ParamKind), "", jen.Id("err"))),
+	)
+}
+
+// renderFormDecoding parses url encoded and multipart forms and assigns the
+// form values to the fields of the payload.
+func renderFormDecoding(gen *jen.Group, param InputParam) {
+	gen.If(
+		jen.Id("err").Op(":=").Id("r").Dot("ParseMultipartForm").Call(jen.Lit(32).Op("<<").Lit(20)),
+		jen.Id("err").Op("!=").Nil().Op("&&").
+			Id("err").Op("!=").Qual(pkgHttp, "ErrNotMultipart"),
+	).Block(
+		jen.Return().Add(renderBindingErrorValue(BindingSource(param.ParamKind), "", jen.Id("err"))),
+	)
+
+	for _, field := range param.FormFields {
+		target := jen.Id(param.VarName).Dot(field.Field)
+
+		gen.If(
+			jen.List(jen.Id("values"), jen.Id("ok")).Op(":=").
+				Id("r").Dot("PostForm").Index(jen.Lit(field.Key)),
+			jen.Id("ok"),
+		).BlockFunc(func(g *jen.Group) {
+			switch {
+			case field.Slice && field.TypeName == "string" && field.TypePackage == "":
+				g.Add(target).Op("=").Id("values")
+
+			case field.Slice:
+				g.Add(target).Op("=").Make(
+					jen.Index().Add(renderTypeName(field.TypePackage, field.TypeName)),
+					jen.Lit(0),
+					jen.Len(jen.Id("values")),
+				)
+				g.For(jen.List(jen.Id("_"), jen.Id("value")).Op(":=").Range().Id("values")).
+					BlockFunc(func(g *jen.Group) {
+						renderConversion(g, "elem", jen.Id("value"), field.TypeName, field.TypePackage,
+							renderBindingErrorValue(BindingSource(param.ParamKind), field.Key, jen.Id("err")))
+						g.Add(target).Op("=").Append(target, jen.Id("elem"))
+					})
+
+			default:
+				renderConversion(g, "value", jen.Id("values").Index(jen.Lit(0)),
+					field.TypeName, field.TypePackage,
+					renderBindingErrorValue(BindingSource(param.ParamKind), field.Key, jen.Id("err")))
+
+				if field.Pointer {
+					g.Add(target).Op("=").Op("&").Id("value")
+				} else {
+					g.Add(target).Op("=").Id("value")
+				}
+			}
+		})
+	}
+}
+
+func renderResolverParam(gen *jen.Group, param InputParam) {
+	gen.Commentf("Resolve parameter using %s.", param.Resolver)
+
+	var stmt jen.Code
+
+	if param.ReturnsError {
+		stmt = jen.List(jen.Id(param.VarName), jen.Id("err"))
+	} else {
+		stmt = jen.Id(param.VarName)
+	}
+
+	gen.Add(stmt).
+		Op(":=").
+		Id("s").Dot(param.Resolver).Dot(mResolve).
+		Call(renderInputVars(param.InputVars))
+
+	if param.ReturnsError {
+		renderIfErr(gen)
+	}
+}
+
+func renderParamType(param InputParam) jen.Code {
+	if param.TypeSlice {
+		return jen.Index().Add(renderTypeName(param.TypePackage, param.TypeName))
+	}
+
+	return renderTypeName(param.TypePackage, param.TypeName)
+}
+
+func renderTypeName(typePackage, typeName string) jen.Code {
+	if typePackage == "" {
+		return jen.Id(typeName)
+	}
+
+	return jen.Qual(typePackage, typeName)
+}
+
+func renderIfErr(gen *jen.Group) {
+	renderIfErrReturn(gen, jen.Id("err"))
+}
+
+func renderIfErrReturn(gen *jen.Group, errValue jen.Code) {
+	gen.If(jen.Id("err").Op("!=").Nil()).Block(jen.Return().Add(errValue))
+}
+
+func renderInputVars(inputVars []InputVar) jen.Code {
+	var varsCode []jen.Code
+
+	for _, inputVar := range inputVars {
+		varsCode = append(varsCode, renderInputVar(inputVar))
+	}
+
+	return jen.List(varsCode...)
+}
+
+func renderInputVar(inputVar InputVar) jen.Code {
+	var prefix string
+	if n := inputVar.PointerDepth; n > 0 {
+		prefix = strings.Repeat("&", n)
+	} else {
+		prefix = strings.Repeat("*", -n)
+	}
+
+	return jen.Id(prefix + inputVar.VarName)
 }
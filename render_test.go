@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "Rewrite the golden files of the generated routers")
+
+const servicesFolder = "./testdata/services"
+
+// renderCase configures the flags of a generated router.
+type renderCase struct {
+	name                 string
+	router               string
+	problemDetails       bool
+	customErrorHandler   bool
+	customRequestReader  bool
+	customResponseWriter bool
+}
+
+var renderCases = []renderCase{
+	{name: "chi", router: "chi"},
+	{name: "chi_custom", router: "chi", customRequestReader: true, customResponseWriter: true},
+	{name: "chi_problem_details", router: "chi", problemDetails: true},
+	{name: "servemux", router: "servemux"},
+	{name: "gorilla", router: "gorilla"},
+	{name: "httprouter", router: "httprouter"},
+}
+
+// apply sets the flags of the case and returns a func to reset them.
+func (c renderCase) apply() func() {
+	routerName = c.router
+	problemDetails = c.problemDetails
+	customErrorHandler = c.customErrorHandler
+	customRequestReader = c.customRequestReader
+	customResponseWriter = c.customResponseWriter
+
+	return func() {
+		routerName = "chi"
+		problemDetails = false
+		customErrorHandler = false
+		customRequestReader = false
+		customResponseWriter = false
+	}
+}
+
+// generate renders the router of the package in folder to filename.
+func generate(folder, filename string) error {
+	source, err := ParsePackage(folder)
+	if err != nil {
+		return fmt.Errorf("parsing: %w", err)
+	}
+
+	collection, err := AnalyzePackage(source)
+	if err != nil {
+		return fmt.Errorf("analyzing: %w", err)
+	}
+
+	return RenderServiceRouter(filename, collection)
+}
+
+func TestRenderGolden(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flowheater")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	for _, c := range renderCases {
+		t.Run(c.name, func(t *testing.T) {
+			defer c.apply()()
+
+			filename := filepath.Join(dir, c.name+".go")
+			if err := generate(servicesFolder, filename); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ioutil.ReadFile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", "golden", c.name+".golden")
+
+			if *update {
+				if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("generated router differs from %s, run the tests with -update to rewrite it", golden)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	flowheaterModule = "github.com/lukasdietrich/flowheater"
+	servicesImport   = flowheaterModule + "/testdata/services"
+	servedModule     = "example.com/served"
+	serveFolder      = "./testdata/serve"
+)
+
+// serveCase is a generated router, the module of its backend and the client
+// tests run against it. The tags select the client tests of a flag and run
+// restricts the tests to the ones, that apply to the flag.
+type serveCase struct {
+	renderCase
+	module  string
+	version string
+	tags    string
+	run     string
+}
+
+var serveCases = []serveCase{
+	{
+		renderCase: renderCase{name: "chi", router: "chi"},
+		module:     pkgChi,
+		version:    "v4.1.2+incompatible",
+	},
+	{
+		renderCase: renderCase{
+			name:                 "chi_custom",
+			router:               "chi",
+			customRequestReader:  true,
+			customResponseWriter: true,
+		},
+		module:  pkgChi,
+		version: "v4.1.2+incompatible",
+		tags:    "custom",
+		run:     "TestCustom",
+	},
+	{
+		renderCase: renderCase{name: "chi_problem_details", router: "chi", problemDetails: true},
+		module:     pkgChi,
+		version:    "v4.1.2+incompatible",
+		tags:       "problem",
+		run:        "TestProblem",
+	},
+	{
+		renderCase: renderCase{name: "servemux", router: "servemux"},
+	},
+	{
+		renderCase: renderCase{name: "gorilla", router: "gorilla"},
+		module:     pkgMux,
+		version:    "v1.8.1",
+	},
+	{
+		renderCase: renderCase{name: "httprouter", router: "httprouter"},
+		module:     pkgRouter,
+		version:    "v1.3.0",
+	},
+}
+
+// TestServe generates the router of the test services into a separate module
+// and runs the client tests in testdata/serve against it.
+func TestServe(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiling the generated routers is slow")
+	}
+
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the go command is not available")
+	}
+
+	root, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range serveCases {
+		t.Run(c.name, func(t *testing.T) {
+			defer c.apply()()
+
+			dir, err := ioutil.TempDir("", "flowheater")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer os.RemoveAll(dir)
+
+			writeServedModule(t, dir, root, c)
+			copyPackage(t, servicesFolder, filepath.Join(dir, "services"))
+			copyPackage(t, serveFolder, filepath.Join(dir, "services"))
+
+			if err := generate(servicesFolder, filepath.Join(dir, "services", "flowheater_gen.go")); err != nil {
+				t.Fatal(err)
+			}
+
+			rewriteImports(t, dir)
+
+			if c.module != "" {
+				if out, err := goCommand(dir, "mod", "download", c.module); err != nil {
+					t.Skipf("%s is not available: %v\n%s", c.module, err, out)
+				}
+			}
+
+			args := []string{"test", "-tags", c.tags}
+			if c.run != "" {
+				args = append(args, "-run", c.run)
+			}
+
+			if out, err := goCommand(dir, append(args, "./...")...); err != nil {
+				t.Fatalf("testing the generated router: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+func writeServedModule(t *testing.T, dir, root string, c serveCase) {
+	t.Helper()
+
+	var mod strings.Builder
+	fmt.Fprintf(&mod, "module %s\n\ngo 1.23\n\n", servedModule)
+	fmt.Fprintf(&mod, "require %s v0.0.0\n\n", flowheaterModule)
+	fmt.Fprintf(&mod, "replace %s => %s\n", flowheaterModule, root)
+
+	if c.module != "" {
+		fmt.Fprintf(&mod, "\nrequire %s %s\n", c.module, c.version)
+	}
+
+	writeFile(t, filepath.Join(dir, "go.mod"), []byte(mod.String()))
+
+	// The checksums of the dependencies of flowheater are reused, so the
+	// served module can be built without downloading them again.
+	sum, err := ioutil.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(dir, "go.sum"), sum)
+}
+
+// copyPackage copies the go files of a folder and its sub folders.
+func copyPackage(t *testing.T, src, dst string) {
+	t.Helper()
+
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".go" {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		return copyFile(path, filepath.Join(dst, rel))
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// rewriteImports replaces the import path of the test services in every file
+// of the served module.
+func rewriteImports(t *testing.T, dir string) {
+	t.Helper()
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".go" {
+			return err
+		}
+
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		src = []byte(strings.ReplaceAll(string(src), servicesImport, servedModule+"/services"))
+		return ioutil.WriteFile(path, src, info.Mode())
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func copyFile(src, dst string) error {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(dst, data, 0644)
+}
+
+func writeFile(t *testing.T, filename string, data []byte) {
+	t.Helper()
+
+	if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func goCommand(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+
+	return cmd.CombinedOutput()
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type request struct {
+	method string
+	path   string
+	header http.Header
+	body   string
+}
+
+type response struct {
+	status int
+	body   string
+	header http.Header
+}
+
+type test struct {
+	name string
+	req  request
+	want response
+}
+
+func header(pairs ...string) http.Header {
+	h := make(http.Header)
+	for i := 0; i < len(pairs); i += 2 {
+		h.Add(pairs[i], pairs[i+1])
+	}
+
+	return h
+}
+
+func get(path string, pairs ...string) request {
+	return request{method: http.MethodGet, path: path, header: header(pairs...)}
+}
+
+func post(path, body string, pairs ...string) request {
+	return request{method: http.MethodPost, path: path, header: header(pairs...), body: body}
+}
+
+func postJSON(path, body string) request {
+	return post(path, body, "Content-Type", "application/json")
+}
+
+// run sends the requests of the tests to the router returned by newRouter.
+// The body of a response must contain the wanted body and every wanted
+// header must match.
+func run(t *testing.T, tests []test) {
+	t.Helper()
+
+	server := httptest.NewServer(newRouter().Handler())
+	defer server.Close()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res := do(t, server, test.req)
+
+			if res.status != test.want.status {
+				t.Fatalf("status = %d, want %d (body %q)", res.status, test.want.status, res.body)
+			}
+
+			if !strings.Contains(res.body, test.want.body) {
+				t.Errorf("body = %q, want %q", res.body, test.want.body)
+			}
+
+			for name, values := range test.want.header {
+				got := headerValue(name, res.header.Values(name))
+				want := headerValue(name, values)
+
+				if got != want {
+					t.Errorf("header %s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+// headerValue joins the values of a header. The order of allowed methods
+// differs between the routers, so they are sorted.
+func headerValue(name string, values []string) string {
+	value := strings.Join(values, ", ")
+
+	if name == "Allow" {
+		methods := strings.Split(value, ", ")
+		sort.Strings(methods)
+		value = strings.Join(methods, ", ")
+	}
+
+	return value
+}
+
+func do(t *testing.T, server *httptest.Server, req request) response {
+	method := req.method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	r, err := http.NewRequest(method, server.URL+req.path, strings.NewReader(req.body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, values := range req.header {
+		r.Header[name] = values
+	}
+
+	// Redirects are returned as they are.
+	client := server.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	res, err := client.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return response{res.StatusCode, string(body), res.Header}
+}
@@ -0,0 +1,17 @@
+package services
+
+import "testing"
+
+func TestConvert(t *testing.T) {
+	run(t, []test{
+		{"numbers", get("/convert/numbers/1.5/-2/true/7"), response{200, `"1.5 -2 true 7"`, nil}},
+		{"invalid float", get("/convert/numbers/x/1/true/7"), response{400, "", nil}},
+		{"invalid bool", get("/convert/numbers/1/1/yes/7"), response{400, "", nil}},
+		{"negative unsigned", get("/convert/numbers/1/1/true/-7"), response{400, "", nil}},
+		{"times", get("/convert/times/2020-01-02T03:04:05+01:00/1m30s"), response{200, `"2020-01-02T02:04:05Z 1m30s"`, nil}},
+		{"invalid time", get("/convert/times/2020-01-02/1s"), response{400, "", nil}},
+		{"invalid duration", get("/convert/times/2020-01-02T03:04:05Z/1"), response{400, "", nil}},
+		{"text", get("/convert/text/abc/raw"), response{200, `"abc raw"`, nil}},
+		{"invalid text", get("/convert/text/abcd/raw"), response{400, "", nil}},
+	})
+}
@@ -0,0 +1,40 @@
+//go:build custom
+
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// newRouter decodes every payload as json regardless of its content type and
+// marks the responses of the custom writer.
+func newRouter() *ServiceRouter {
+	return &ServiceRouter{
+		PayloadService: &PayloadService{},
+		StatusService:  &StatusService{},
+		// The mounted handlers are read, when the handler is built.
+		SiteService: &SiteService{},
+		ReadRequest: func(r *http.Request, v interface{}) error {
+			return json.NewDecoder(r.Body).Decode(v)
+		},
+		WriteResponse: func(w http.ResponseWriter, r *http.Request, v interface{}) error {
+			w.Header().Set("X-Writer", "custom")
+			return json.NewEncoder(w).Encode(v)
+		},
+	}
+}
+
+func TestCustom(t *testing.T) {
+	run(t, []test{
+		{"reader", post("/payloads", `{"name":"al"}`, "Content-Type", "text/plain"), response{200, `"al 0 [] nil"`, nil}},
+		{"reader error", postJSON("/payloads", `{`), response{400, "", nil}},
+		{"consumes", post("/payloads/xml", `{"name":"al"}`, "Content-Type", "text/xml"), response{200, `"al 0 [] nil"`, nil}},
+		{"consumes without content type", post("/payloads/xml", `{"name":"al"}`), response{200, `"al 0 [] nil"`, nil}},
+		{"not consumed", postJSON("/payloads/xml", `{"name":"al"}`), response{415, "", nil}},
+		{"writer", get("/status"), response{200, `"ok"`, header("X-Writer", "custom")}},
+		{"writer status", post("/status", ""), response{201, `"created"`, header("X-Writer", "custom")}},
+		{"writer void", request{method: http.MethodDelete, path: "/status"}, response{204, "", header("X-Writer", "")}},
+	})
+}
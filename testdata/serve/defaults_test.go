@@ -0,0 +1,12 @@
+package services
+
+import "testing"
+
+func TestDefaults(t *testing.T) {
+	run(t, []test{
+		{"defaults", get("/defaults"), response{200, `"20 name nil [a] nil"`, nil}},
+		{"values", get("/defaults?limit=5&sort=id&q=x&tags=c", "X-Trace", "7"), response{200, `"5 id x [c] 7"`, nil}},
+		{"empty optional", get("/defaults?q="), response{200, `"20 name  [a]`, nil}},
+		{"invalid optional", get("/defaults", "X-Trace", "x"), response{400, "", nil}},
+	})
+}
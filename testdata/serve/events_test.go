@@ -0,0 +1,52 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEvents(t *testing.T) {
+	events := header("Content-Type", "text/event-stream", "Cache-Control", "no-cache")
+
+	run(t, []test{
+		{"channel", get("/events/progress"), response{200, "data: {\"percent\":50}\n\ndata: {\"percent\":100}\n\n", events}},
+		{"named iterator", get("/events/ticks"), response{200, "data: 1\n\ndata: 2\n\ndata: 3\n\n", events}},
+		{"iter.Seq", get("/events/seq"), response{200, "data: \"a\"\n\ndata: \"b\"\n\n", events}},
+	})
+}
+
+func TestEventsCancelled(t *testing.T) {
+	server := httptest.NewServer(newRouter().Handler())
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events/endless", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	line, err := bufio.NewReader(res.Body).ReadString('\n')
+	if err != nil || line != "data: 0\n" {
+		t.Fatalf("first event = %q, %v", line, err)
+	}
+
+	cancel()
+
+	select {
+	case <-endlessStopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the channel was not stopped after the request was cancelled")
+	}
+}
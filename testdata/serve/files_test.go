@@ -0,0 +1,35 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFiles(t *testing.T) {
+	var (
+		future = time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+		past   = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+	)
+
+	run(t, []test{
+		{"reader", get("/files/reader"), response{200, "hello world", header("Content-Type", "text/plain; charset=utf-8")}},
+		{"reader range", get("/files/reader", "Range", "bytes=0-4"),
+			response{206, "hello", header("Content-Range", "bytes 0-4/11")}},
+		{"reader not modified", get("/files/reader", "If-Modified-Since", future), response{200, "hello world", nil}},
+		{"plain", get("/files/plain", "Range", "bytes=0-1"), response{200, "hello", header("Content-Type", "text/plain; charset=utf-8")}},
+		{"closer", get("/files/closer"), response{200, "closed", header("Content-Type", "application/octet-stream")}},
+		{"file", get("/files/file"), response{200, "package services", header("Accept-Ranges", "bytes")}},
+		{"file range", get("/files/file", "Range", "bytes=0-6"), response{206, "package", nil}},
+		{"file not modified", get("/files/file", "If-Modified-Since", future), response{304, "", nil}},
+		{"file modified", get("/files/file", "If-Modified-Since", past), response{200, "package services", nil}},
+		{"fs", get("/files/fs", "Range", "bytes=0-6"), response{206, "package", header("Content-Type", "text/x-go")}},
+		{"fs not modified", get("/files/fs", "If-Modified-Since", future), response{304, "", nil}},
+		{"error", get("/files/missing/false"), response{404, "no such file", nil}},
+		{"nil", get("/files/missing/true"), response{200, "", nil}},
+	})
+
+	if !readerClosed.Load() {
+		t.Error("the returned io.ReadCloser was not closed")
+	}
+}
@@ -0,0 +1,12 @@
+package services
+
+import "testing"
+
+func TestHeaders(t *testing.T) {
+	run(t, []test{
+		{"header and cookie", get("/headers", "X-Api-Version", "3", "Cookie", "sid=abc"), response{200, `"3 abc"`, nil}},
+		{"missing header", get("/headers", "Cookie", "sid=abc"), response{400, "", nil}},
+		{"missing cookie", get("/headers", "X-Api-Version", "3"), response{400, "", nil}},
+		{"invalid header", get("/headers", "X-Api-Version", "v3", "Cookie", "sid=abc"), response{400, "", nil}},
+	})
+}
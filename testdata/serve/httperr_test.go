@@ -0,0 +1,31 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHttpErrors(t *testing.T) {
+	run(t, []test{
+		{"bad request", get("/errors/bad-request"), response{400, "bad kind\n", nil}},
+		{"default message", get("/errors/unauthorized"), response{401, "Unauthorized\n", nil}},
+		{"forbidden", get("/errors/forbidden"), response{403, "no access\n", nil}},
+		{"not found", get("/errors/not-found"), response{404, "no such kind\n", nil}},
+		{"conflict", get("/errors/conflict"), response{409, "kind exists\n", nil}},
+		{"gone", get("/errors/gone"), response{410, "kind is gone\n", nil}},
+		{"unprocessable", get("/errors/unprocessable"), response{422, "cannot process kind\n", nil}},
+		{"too many", get("/errors/too-many"), response{429, "slow down\n", nil}},
+		{"status only", get("/errors/teapot"), response{418, "I'm a teapot\n", nil}},
+		{"wrapped", get("/errors/wrapped"), response{503, "try again\n", nil}},
+		{"internal", get("/errors/internal"), response{500, "", nil}},
+		{"none", get("/errors/none"), response{204, "", nil}},
+		{"conversion", get("/query/x?limit=abc"), response{400, "query parameter limit: invalid value\n", nil}},
+		{"missing", postJSON("/requests/search/7", `{}`), response{400, "header parameter X-Trace: missing parameter\n", nil}},
+		{"rule", get("/validation?sort=id&page=0"), response{400, "query parameter page: must be at least 1\n", nil}},
+		{"malformed payload", postJSON("/payloads", `{"name":1}`), response{400, "body: invalid value\n", nil}},
+		{"validate method", postJSON("/validation", `{"name":"root","created":"2020-01-01T00:00:00Z","parent":{}}`),
+			response{400, "body: invalid value\n", nil}},
+		{"unsupported media type", post("/payloads", "", "Content-Type", "text/plain"),
+			response{http.StatusUnsupportedMediaType, "unsupported media type\n", nil}},
+	})
+}
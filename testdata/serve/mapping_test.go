@@ -0,0 +1,18 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMapping(t *testing.T) {
+	run(t, []test{
+		{"mapped", get("/mapping/not-found"), response{404, "Not Found\n", nil}},
+		{"wrapped", get("/mapping/wrapped"), response{404, "", nil}},
+		{"initialized by call", get("/mapping/conflict"), response{409, "", nil}},
+		{"imported", get("/mapping/read-only"), response{503, "Service Unavailable\n", nil}},
+		{"unmapped", get("/mapping/other"), response{500, "", nil}},
+		{"endpoint", request{method: http.MethodDelete, path: "/mapping/not-found"}, response{410, "", nil}},
+		{"endpoint falls back to service", request{method: http.MethodDelete, path: "/mapping/conflict"}, response{409, "", nil}},
+	})
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMethods(t *testing.T) {
+	run(t, []test{
+		{"first", get("/methods/resource"), response{200, `"read"`, nil}},
+		{"second", request{method: http.MethodPut, path: "/methods/resource"}, response{200, `"read"`, nil}},
+		{"custom", request{method: "PROPFIND", path: "/methods/resource"}, response{200, `"found"`, nil}},
+		{"any", request{method: http.MethodPatch, path: "/methods/any"}, response{200, `"any"`, nil}},
+		{"any webdav", request{method: "MKCOL", path: "/methods/any"}, response{200, `"any"`, nil}},
+	})
+}
+
+func TestMethodsAllowed(t *testing.T) {
+	allow := header("Allow", "GET, HEAD, OPTIONS, PROPFIND, PUT")
+
+	run(t, []test{
+		{"head", request{method: http.MethodHead, path: "/methods/resource"}, response{200, "", header("Content-Type", "application/json")}},
+		{"options", request{method: http.MethodOptions, path: "/methods/resource"}, response{204, "", allow}},
+		{"not allowed", request{method: http.MethodDelete, path: "/methods/resource"}, response{405, "", allow}},
+		{"declared options", request{method: http.MethodOptions, path: "/methods/preflight"}, response{200, `"preflight"`, nil}},
+		{"options of any", request{method: http.MethodOptions, path: "/methods/any"}, response{200, `"any"`, nil}},
+	})
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMount(t *testing.T) {
+	run(t, []test{
+		{"field", get("/site/static/a.css"), response{200, "static /a.css", nil}},
+		{"any method", request{method: http.MethodPost, path: "/site/static/a.css"}, response{200, "static /a.css", nil}},
+		{"full path", get("/site/debug/vars"), response{200, "debug /site/debug/vars", nil}},
+		{"mux", get("/site/mux/hello"), response{200, "mux /hello", nil}},
+		{"handler func", get("/site/func/x"), response{200, "func /x", nil}},
+		{"method", get("/site/legacy/x"), response{200, "legacy /x", nil}},
+		{"endpoint", get("/site/pages/home"), response{200, `"home"`, nil}},
+	})
+}
+
+func newMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("mux " + r.URL.Path))
+	})
+
+	return mux
+}
+
+func echoFunc(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("func " + r.URL.Path))
+}
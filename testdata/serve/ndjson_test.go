@@ -0,0 +1,14 @@
+package services
+
+import "testing"
+
+func TestNdjson(t *testing.T) {
+	ndjson := header("Content-Type", "application/x-ndjson")
+
+	run(t, []test{
+		{"slice", get("/reports/rows"), response{200, "{\"id\":1,\"name\":\"a\"}\n{\"id\":2,\"name\":\"b\"}\n", ndjson}},
+		{"iterator", get("/reports/seq"), response{200, "1\n2\n3\n", ndjson}},
+		{"channel", get("/reports/channel"), response{200, "\"a\"\n\"b\"\n", ndjson}},
+		{"slice as events", get("/reports/events"), response{200, "data: 1\n\ndata: 2\n\n", header("Content-Type", "text/event-stream")}},
+	})
+}
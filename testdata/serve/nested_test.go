@@ -0,0 +1,13 @@
+package services
+
+import "testing"
+
+func TestNested(t *testing.T) {
+	run(t, []test{
+		{"embedded", get("/api/health"), response{200, `"ok"`, nil}},
+		{"mounted", get("/api/v1/things/items/3"), response{200, `"thing"`, nil}},
+		{"inherited errors", get("/api/v1/things/items/0"), response{404, "", nil}},
+		{"replaced", get("/api/v1/things/health"), response{200, `"things ok"`, nil}},
+		{"unmounted", get("/things/items/3"), response{404, "", nil}},
+	})
+}
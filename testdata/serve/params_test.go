@@ -0,0 +1,13 @@
+package services
+
+import "testing"
+
+func TestParams(t *testing.T) {
+	run(t, []test{
+		{"mapped name", get("/params/users/42"), response{200, "42", nil}},
+		{"mapped name conversion", get("/params/users/abc"), response{400, "", nil}},
+		{"catch-all", get("/params/files/a/b/c.txt"), response{200, `"a/b/c.txt"`, nil}},
+		{"catch-all segment", get("/params/files/readme"), response{200, `"readme"`, nil}},
+		{"anonymous catch-all", get("/params/assets/x/y"), response{200, `"X/Y"`, nil}},
+	})
+}
@@ -0,0 +1,32 @@
+package services
+
+import "testing"
+
+const multipartBody = "--b\r\n" +
+	"Content-Disposition: form-data; name=\"name\"\r\n\r\nal\r\n" +
+	"--b\r\n" +
+	"Content-Disposition: form-data; name=\"tags\"\r\n\r\nx\r\n" +
+	"--b--\r\n"
+
+func TestPayload(t *testing.T) {
+	run(t, []test{
+		{"json", postJSON("/payloads", `{"name":"al","age":3,"tags":["x"],"score":1.5}`), response{200, `"al 3 [x] 1.5"`, nil}},
+		{"json charset", post("/payloads", `{"name":"al"}`, "Content-Type", "application/json; charset=utf-8"),
+			response{200, `"al 0 [] nil"`, nil}},
+		{"no content type", post("/payloads", `{"name":"al"}`), response{200, `"al 0 [] nil"`, nil}},
+		{"xml", post("/payloads", `<Profile><name>al</name><tag>x</tag><tag>y</tag></Profile>`, "Content-Type", "application/xml"),
+			response{200, `"al 0 [x y] nil"`, nil}},
+		{"form", post("/payloads", "name=al&age=3&tags=x&tags=y&score=2", "Content-Type", "application/x-www-form-urlencoded"),
+			response{200, `"al 3 [x y] 2"`, nil}},
+		{"invalid form", post("/payloads", "age=x", "Content-Type", "application/x-www-form-urlencoded"),
+			response{400, "", nil}},
+		{"multipart", post("/payloads", multipartBody, "Content-Type", "multipart/form-data; boundary=b"),
+			response{200, `"al 0 [x] nil"`, nil}},
+		{"unsupported", post("/payloads", "al", "Content-Type", "text/plain"), response{415, "", nil}},
+		{"malformed json", postJSON("/payloads", `{`), response{400, "", nil}},
+		{"consumes", post("/payloads/xml", `<Profile><name>al</name></Profile>`, "Content-Type", "text/xml"),
+			response{200, `"al 0 [] nil"`, nil}},
+		{"not consumed", postJSON("/payloads/xml", `{"name":"al"}`), response{415, "", nil}},
+		{"slice", postJSON("/payloads/batch", `[{},{}]`), response{200, "2", nil}},
+	})
+}
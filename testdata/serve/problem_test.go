@@ -0,0 +1,26 @@
+//go:build problem
+
+package services
+
+import "testing"
+
+func TestProblem(t *testing.T) {
+	problem := header("Content-Type", "application/problem+json")
+
+	run(t, []test{
+		{"public", get("/errors/not-found"),
+			response{404, `{"type":"about:blank","title":"Not Found","status":404,"detail":"no such kind","instance":"/errors/not-found"}`, problem}},
+		{"status only", get("/errors/teapot"),
+			response{418, `{"type":"about:blank","title":"I'm a teapot","status":418,"instance":"/errors/teapot"}`, problem}},
+		{"internal", get("/errors/internal"),
+			response{500, `{"type":"about:blank","title":"Internal Server Error","status":500,"instance":"/errors/internal"}`, problem}},
+		{"invalid param", get("/validation?sort=id&page=0"),
+			response{400, `"invalid-params":[{"name":"page","in":"query","reason":"must be at least 1"}]`, problem}},
+		{"invalid conversion", get("/query/x?limit=abc"),
+			response{400, `"detail":"query parameter limit: invalid value","instance":"/query/x","invalid-params":[{"name":"limit","in":"query","reason":"invalid value"}]`, problem}},
+		{"invalid payload", postJSON("/payloads", `{"name":1}`),
+			response{400, `"detail":"body: invalid value","instance":"/payloads"}`, problem}},
+		{"method not allowed", request{method: "DELETE", path: "/errors/x"},
+			response{405, `"status":405`, problem}},
+	})
+}
@@ -0,0 +1,20 @@
+package services
+
+import "testing"
+
+func TestProduces(t *testing.T) {
+	run(t, []test{
+		{"csv", get("/export/rows"), response{200, "id,name\n1,a\n2,\"b,c\"\n", header("Content-Type", "text/csv; charset=utf-8")}},
+		{"json", get("/export/rows", "Accept", "application/json"),
+			response{200, `[{"id":1,"name":"a"},{"id":2,"name":"b,c"}]`, header("Content-Type", "application/json")}},
+		{"not produced", get("/export/rows", "Accept", "application/xml"), response{406, "", nil}},
+		{"default json", get("/export/count"), response{200, "42", header("Content-Type", "application/json")}},
+		{"xml", get("/export/count", "Accept", "application/xml"), response{200, "<int>42</int>", header("Content-Type", "application/xml")}},
+		{"text", get("/export/count", "Accept", "text/plain"), response{200, "42", header("Content-Type", "text/plain; charset=utf-8")}},
+		{"quality", get("/export/count", "Accept", "application/json;q=0.5, text/plain"),
+			response{200, "42", header("Content-Type", "text/plain; charset=utf-8")}},
+		{"wildcard", get("/export/count", "Accept", "text/*"), response{200, "42", header("Content-Type", "text/plain; charset=utf-8")}},
+		{"stringer", get("/export/level", "Accept", "text/plain"), response{200, "high", nil}},
+		{"map as xml", get("/export/counts", "Accept", "application/xml"), response{406, "", nil}},
+	})
+}
@@ -0,0 +1,12 @@
+package services
+
+import "testing"
+
+func TestQuery(t *testing.T) {
+	run(t, []test{
+		{"query", get("/query?page=2&size=5"), response{200, "[2,5]", nil}},
+		{"query and path", get("/query/books?q=go&limit=3"), response{200, `"books go 3"`, nil}},
+		{"missing", get("/query?size=5"), response{400, "", nil}},
+		{"overflow", get("/query/books?q=go&limit=300"), response{400, "", nil}},
+	})
+}
@@ -0,0 +1,18 @@
+package services
+
+import "testing"
+
+func TestRequests(t *testing.T) {
+	run(t, []test{
+		{"struct", post("/requests/search/7?q=x&tag=a&tag=b", `{"name":"n"}`,
+			"Content-Type", "application/json", "X-Trace", "t", "Cookie", "sid=s"),
+			response{200, `"7 x 1 [a b] t s n []"`, nil}},
+		{"struct page", post("/requests/search/7?page=3", `{}`, "Content-Type", "application/json", "X-Trace", "t"),
+			response{200, `"7  3 [] t nil  []"`, nil}},
+		{"struct missing header", postJSON("/requests/search/7", `{}`), response{400, "", nil}},
+		{"struct invalid path", postJSON("/requests/search/x", `{}`), response{400, "", nil}},
+		{"struct invalid payload", postJSON("/requests/search/7", `{`), response{400, "", nil}},
+		{"form", post("/requests/signup", "name=n&email=e", "Content-Type", "application/x-www-form-urlencoded"),
+			response{200, `"n e"`, nil}},
+	})
+}
@@ -0,0 +1,19 @@
+package services
+
+import "testing"
+
+func TestResponses(t *testing.T) {
+	run(t, []test{
+		{"struct", post("/responses?name=n", ""), response{201, `"n"`, header(
+			"Location", "/responses/n",
+			"X-Total-Count", "1",
+			"X-Tag", "a", "X-Tag", "b",
+			"X-Extra", "x",
+			"Expires", "",
+			"Set-Cookie", "sid=n",
+		)}},
+		{"status field", post("/responses?name=accepted", ""), response{202, `"accepted"`, nil}},
+		{"text", post("/responses?name=n", "", "Accept", "text/plain"), response{201, "n", header("Location", "/responses/n")}},
+		{"empty", get("/responses/redirect"), response{303, "", header("Location", "/responses/target")}},
+	})
+}
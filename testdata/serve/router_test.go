@@ -0,0 +1,34 @@
+//go:build !custom
+
+package services
+
+func newRouter() *ServiceRouter {
+	return &ServiceRouter{
+		QueryService:      &QueryService{},
+		HeaderService:     &HeaderService{},
+		ConvertService:    &ConvertService{},
+		SliceService:      &SliceService{},
+		DefaultService:    &DefaultService{},
+		RequestService:    &RequestService{},
+		ValidationService: &ValidationService{},
+		PayloadService:    &PayloadService{},
+		ExportService:     &ExportService{},
+		StatusService:     &StatusService{},
+		ResponseService:   &ResponseService{},
+		ErrorService:      &ErrorService{},
+		MappingService:    &MappingService{},
+		FileService:       &FileService{},
+		EventService:      &EventService{},
+		ReportService:     &ReportService{},
+		MethodService:     &MethodService{},
+		RouteService:      &RouteService{},
+		ParamService:      &ParamService{},
+		ApiService:        &ApiService{V1: &ThingService{}},
+		SiteService: &SiteService{
+			Static: EchoHandler{Name: "static"},
+			Debug:  &EchoHandler{Name: "debug"},
+			Mux:    newMux(),
+			Func:   echoFunc,
+		},
+	}
+}
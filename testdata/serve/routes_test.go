@@ -0,0 +1,12 @@
+package services
+
+import "testing"
+
+func TestRoutes(t *testing.T) {
+	run(t, []test{
+		{"index", get("/routes"), response{200, `"index"`, nil}},
+		{"trailing slash", get("/routes/dir/"), response{200, `"dir"`, nil}},
+		{"no trailing slash", get("/routes/dir"), response{200, `"file"`, nil}},
+		{"below trailing slash", get("/routes/dir/x"), response{404, "", nil}},
+	})
+}
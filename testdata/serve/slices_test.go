@@ -0,0 +1,11 @@
+package services
+
+import "testing"
+
+func TestSlices(t *testing.T) {
+	run(t, []test{
+		{"slices", get("/slices/1,2,3?tags=a&tags=b", "Accept-Language", "en,de"), response{200, `"[1 2 3] [a b] [en de]"`, nil}},
+		{"single values", get("/slices/1?tags=a", "Accept-Language", "en"), response{200, `"[1] [a] [en]"`, nil}},
+		{"invalid element", get("/slices/1,x?tags=a", "Accept-Language", "en"), response{400, "", nil}},
+	})
+}
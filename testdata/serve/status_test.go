@@ -0,0 +1,15 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	run(t, []test{
+		{"declared", post("/status", ""), response{201, `"created"`, nil}},
+		{"void", request{method: http.MethodDelete, path: "/status"}, response{204, "", nil}},
+		{"declared void", post("/status/accept", ""), response{202, "", nil}},
+		{"implicit", get("/status"), response{200, `"ok"`, nil}},
+	})
+}
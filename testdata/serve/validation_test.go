@@ -0,0 +1,23 @@
+package services
+
+import "testing"
+
+func TestValidation(t *testing.T) {
+	const valid = `"created":"2020-01-01T00:00:00Z","parent":{}`
+
+	run(t, []test{
+		{"valid", postJSON("/validation", `{"name":"al",`+valid+`}`), response{200, `"al"`, nil}},
+		{"required", postJSON("/validation", `{`+valid+`}`), response{400, "", nil}},
+		{"min length", postJSON("/validation", `{"name":"a",`+valid+`}`), response{400, "", nil}},
+		{"max length", postJSON("/validation", `{"name":"al","tags":["a","b","c","d"],`+valid+`}`), response{400, "", nil}},
+		{"max uint", postJSON("/validation", `{"name":"al","age":151,`+valid+`}`), response{400, "", nil}},
+		{"max float", postJSON("/validation", `{"name":"al","ratio":1.5,`+valid+`}`), response{400, "", nil}},
+		{"required zeroer", postJSON("/validation", `{"name":"al","parent":{}}`), response{400, "", nil}},
+		{"required pointer", postJSON("/validation", `{"name":"al","created":"2020-01-01T00:00:00Z"}`), response{400, "", nil}},
+		{"validate method", postJSON("/validation", `{"name":"root",`+valid+`}`), response{400, "", nil}},
+		{"malformed", postJSON("/validation", `{"name":1}`), response{400, "", nil}},
+		{"struct", get("/validation?sort=id"), response{200, `"1 id"`, nil}},
+		{"struct min", get("/validation?sort=id&page=0"), response{400, "", nil}},
+		{"struct required", get("/validation"), response{400, "", nil}},
+	})
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Code is converted using encoding.TextUnmarshaler.
+type Code [3]byte
+
+func (c *Code) UnmarshalText(text []byte) error {
+	if len(text) != len(c) {
+		return errors.New("a code has three characters")
+	}
+
+	copy(c[:], text)
+	return nil
+}
+
+// ConvertService converts path parameters to builtin and text types.
+// Path: /convert
+type ConvertService struct{}
+
+// Numbers converts floats, booleans and unsigned integers.
+// Path: /numbers/{f}/{g}/{ok}/{u}
+func (ConvertService) Numbers(f float64, g float32, ok bool, u uint16) string {
+	return fmt.Sprintf("%g %g %t %d", f, g, ok, u)
+}
+
+// Times converts RFC 3339 times and durations.
+// Path: /times/{at}/{timeout}
+func (ConvertService) Times(at time.Time, timeout time.Duration) string {
+	return at.UTC().Format(time.RFC3339) + " " + timeout.String()
+}
+
+// Text converts text unmarshalers and bytes.
+// Path: /text/{code}/{raw}
+func (ConvertService) Text(code Code, raw []byte) string {
+	return string(code[:]) + " " + string(raw)
+}
@@ -0,0 +1,31 @@
+package services
+
+import "fmt"
+
+// DefaultService binds optional parameters and defaults.
+// Path: /defaults
+type DefaultService struct{}
+
+// Page falls back to defaults and binds absent pointers to nil.
+// Path: /
+// Query: limit, sort, q, tags
+// Header: trace=X-Trace
+// Default: limit=20, sort=name, tags=a
+func (DefaultService) Page(limit int, sort string, q *string, tags []string, trace *int) string {
+	return fmt.Sprintf("%d %s %s %v %s", limit, sort, optional(q), tags, optional(trace))
+}
+
+func optional(value interface{}) string {
+	switch value := value.(type) {
+	case *string:
+		if value != nil {
+			return *value
+		}
+	case *int:
+		if value != nil {
+			return fmt.Sprint(*value)
+		}
+	}
+
+	return "nil"
+}
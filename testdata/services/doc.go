@@ -0,0 +1,3 @@
+// Package services is the input of the golden and serve tests. Each file
+// declares the services exercising one group of annotations.
+package services
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"iter"
+)
+
+// endlessStopped is closed, when EventService.Endless stops sending.
+var endlessStopped = make(chan struct{})
+
+// Progress is sent as an event.
+type Progress struct {
+	Percent int `json:"percent"`
+}
+
+// Ticks is an iterator declared as a named function type.
+type Ticks func(yield func(int) bool)
+
+// EventService sends server-sent events.
+// Path: /events
+type EventService struct{}
+
+// Progress sends the values of a channel.
+// Path: /progress
+func (EventService) Progress(ctx context.Context) <-chan Progress {
+	ch := make(chan Progress)
+
+	go func() {
+		defer close(ch)
+
+		for percent := 50; percent <= 100; percent += 50 {
+			select {
+			case ch <- Progress{Percent: percent}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// Endless sends events until the request is cancelled.
+// Path: /endless
+func (EventService) Endless(ctx context.Context) <-chan int {
+	ch := make(chan int)
+
+	go func() {
+		defer close(endlessStopped)
+
+		for n := 0; ; n++ {
+			select {
+			case ch <- n:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// Ticks sends the values of a named iterator.
+// Path: /ticks
+func (EventService) Ticks() Ticks {
+	return func(yield func(int) bool) {
+		for n := 1; n <= 3; n++ {
+			if !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+// Seq sends the values of an iter.Seq.
+// Path: /seq
+func (EventService) Seq() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, s := range []string{"a", "b"} {
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
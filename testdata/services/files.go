@@ -0,0 +1,72 @@
+package services
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/lukasdietrich/flowheater/httperr"
+)
+
+// readerClosed is set, when the reader returned by FileService.Closer is
+// closed.
+var readerClosed atomic.Bool
+
+type closeRecorder struct {
+	io.Reader
+}
+
+func (closeRecorder) Close() error {
+	readerClosed.Store(true)
+	return nil
+}
+
+// FileService streams readers and files. The files are read from the
+// directory of the package, which is the working directory of its tests.
+// Path: /files
+type FileService struct{}
+
+// Reader returns a seekable reader.
+// Path: /reader
+// Content-Type: text/plain; charset=utf-8
+func (FileService) Reader() io.Reader {
+	return strings.NewReader("hello world")
+}
+
+// Plain returns a reader, that cannot seek.
+// Path: /plain
+func (FileService) Plain() io.Reader {
+	return io.LimitReader(strings.NewReader("hello world"), 5)
+}
+
+// Closer returns a reader, that records being closed.
+// Path: /closer
+// Content-Type: application/octet-stream
+func (FileService) Closer() io.ReadCloser {
+	return closeRecorder{strings.NewReader("closed")}
+}
+
+// File returns a file of the package.
+// Path: /file
+func (FileService) File() (*os.File, error) {
+	return os.Open("files.go")
+}
+
+// FS returns a file of the package through fs.FS.
+// Path: /fs
+// Content-Type: text/x-go
+func (FileService) FS() (fs.File, error) {
+	return os.DirFS(".").Open("files.go")
+}
+
+// Missing returns an error or no reader.
+// Path: /missing/{found}
+func (FileService) Missing(found bool) (io.ReadCloser, error) {
+	if !found {
+		return nil, httperr.NotFound("no such file")
+	}
+
+	return nil, nil
+}
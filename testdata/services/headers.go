@@ -0,0 +1,15 @@
+package services
+
+import "fmt"
+
+// HeaderService binds headers and cookies.
+// Path: /headers
+type HeaderService struct{}
+
+// Version reads the api version from a header and the session from a cookie.
+// Path: /
+// Header: apiVersion=X-Api-Version
+// Cookie: session=sid
+func (HeaderService) Version(apiVersion int, session string) string {
+	return fmt.Sprintf("%d %s", apiVersion, session)
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/lukasdietrich/flowheater/httperr"
+)
+
+// teapotError only implements httperr.StatusError.
+type teapotError struct{}
+
+func (teapotError) Error() string {
+	return "secret details"
+}
+
+func (teapotError) StatusCode() int {
+	return 418
+}
+
+// ErrorService returns errors with status codes.
+// Path: /errors
+type ErrorService struct{}
+
+// Get returns the error of a kind.
+// Path: /{kind}
+func (ErrorService) Get(kind string) error {
+	switch kind {
+	case "bad-request":
+		return httperr.BadRequest("bad kind")
+	case "unauthorized":
+		return httperr.Unauthorized("")
+	case "forbidden":
+		return httperr.Forbidden("no access")
+	case "not-found":
+		return httperr.NotFound("no such kind")
+	case "conflict":
+		return httperr.Conflict("kind exists")
+	case "gone":
+		return httperr.Gone("kind is gone")
+	case "unprocessable":
+		return httperr.UnprocessableEntity("cannot process kind")
+	case "too-many":
+		return httperr.TooManyRequests("slow down")
+	case "teapot":
+		return teapotError{}
+	case "wrapped":
+		return &httperr.HttpError{Status: 503, Message: "try again", Err: errors.New("secret details")}
+	case "internal":
+		return errors.New("secret details")
+	}
+
+	return nil
+}
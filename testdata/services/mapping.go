@@ -0,0 +1,53 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lukasdietrich/flowheater/testdata/services/store"
+)
+
+var (
+	ErrNotFound = errors.New("item does not exist")
+	ErrConflict = newConflict()
+)
+
+func newConflict() error {
+	return errors.New("item already exists")
+}
+
+// MappingService maps package level errors to status codes.
+// Path: /mapping
+// Errors: ErrNotFound=404, ErrConflict=409, store.ErrReadOnly=503
+type MappingService struct{}
+
+// Get returns the error of a kind.
+// Path: /{kind}
+func (MappingService) Get(kind string) error {
+	return mappedError(kind)
+}
+
+// Delete maps ErrNotFound differently than its service.
+// Path: /{kind}
+// Method: DELETE
+// Errors: ErrNotFound=410
+func (MappingService) Delete(kind string) error {
+	return mappedError(kind)
+}
+
+func mappedError(kind string) error {
+	switch kind {
+	case "not-found":
+		return ErrNotFound
+	case "wrapped":
+		return fmt.Errorf("lookup: %w", ErrNotFound)
+	case "conflict":
+		return ErrConflict
+	case "read-only":
+		return store.ErrReadOnly
+	case "other":
+		return errors.New("item does not exist")
+	}
+
+	return nil
+}
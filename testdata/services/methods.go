@@ -0,0 +1,33 @@
+package services
+
+// MethodService accepts multiple, custom and any methods.
+// Path: /methods
+type MethodService struct{}
+
+// Read accepts several methods.
+// Path: /resource
+// Method: GET, put
+func (MethodService) Read() string {
+	return "read"
+}
+
+// Find accepts a custom method.
+// Path: /resource
+// Method: PROPFIND
+func (MethodService) Find() string {
+	return "found"
+}
+
+// Any accepts every method.
+// Path: /any
+// Method: ANY
+func (MethodService) Any() string {
+	return "any"
+}
+
+// Preflight answers OPTIONS requests itself.
+// Path: /preflight
+// Method: GET, OPTIONS
+func (MethodService) Preflight() string {
+	return "preflight"
+}
@@ -0,0 +1,41 @@
+package services
+
+import "net/http"
+
+// EchoHandler writes its name and the path of the request.
+type EchoHandler struct {
+	Name string
+}
+
+func (h EchoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(h.Name + " " + r.URL.Path))
+}
+
+// SiteService mounts handlers.
+// Path: /site
+type SiteService struct {
+	// Mount: /static
+	Static EchoHandler
+
+	// Mount: /debug
+	// Strip-Prefix: false
+	Debug *EchoHandler
+
+	// Mount: /mux
+	Mux *http.ServeMux
+
+	// Mount: /func
+	Func http.HandlerFunc
+}
+
+// Legacy returns a mounted handler.
+// Mount: /legacy
+func (*SiteService) Legacy() http.Handler {
+	return EchoHandler{Name: "legacy"}
+}
+
+// Page returns a page.
+// Path: /pages/{page}
+func (*SiteService) Page(page string) string {
+	return page
+}
@@ -0,0 +1,46 @@
+package services
+
+import "iter"
+
+// ReportService writes reports as newline delimited json.
+// Path: /reports
+type ReportService struct{}
+
+// Rows writes a slice as lines.
+// Path: /rows
+// Stream: ndjson
+func (ReportService) Rows() ([]Row, error) {
+	return []Row{{1, "a"}, {2, "b"}}, nil
+}
+
+// Seq writes the values of an iterator as lines.
+// Path: /seq
+// Stream: NDJSON
+func (ReportService) Seq() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for n := 1; n <= 3; n++ {
+			if !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+// Channel writes the values of a channel as lines.
+// Path: /channel
+// Stream: ndjson
+func (ReportService) Channel() <-chan string {
+	ch := make(chan string, 2)
+	ch <- "a"
+	ch <- "b"
+	close(ch)
+
+	return ch
+}
+
+// Events sends a slice as events.
+// Path: /events
+// Stream: events
+func (ReportService) Events() []int {
+	return []int{1, 2}
+}
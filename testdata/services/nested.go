@@ -0,0 +1,47 @@
+package services
+
+import "errors"
+
+// ErrNoThing is mapped by the api and inherited by mounted services.
+var ErrNoThing = errors.New("no thing")
+
+// HealthBase is embedded to share an endpoint.
+type HealthBase struct{}
+
+// Health reports the health of a service.
+// Path: /health
+func (HealthBase) Health() string {
+	return "ok"
+}
+
+// ApiService mounts other services.
+// Path: /api
+// Errors: ErrNoThing=404
+type ApiService struct {
+	HealthBase
+
+	// Mount: /v1
+	V1 *ThingService
+}
+
+// ThingService is only registered below the api.
+// Path: /things
+type ThingService struct {
+	HealthBase
+}
+
+// Health replaces the promoted endpoint.
+// Path: /health
+func (*ThingService) Health() string {
+	return "things ok"
+}
+
+// Get returns a thing.
+// Path: /items/{id}
+func (*ThingService) Get(id int64) (string, error) {
+	if id == 0 {
+		return "", ErrNoThing
+	}
+
+	return "thing", nil
+}
@@ -0,0 +1,27 @@
+package services
+
+import "strings"
+
+// ParamService maps path parameters and binds catch-alls.
+// Path: /params
+type ParamService struct{}
+
+// User maps an argument to a path parameter with a dash.
+// Path: /users/{user-id}
+// Param: userID=user-id
+func (ParamService) User(userID int64) int64 {
+	return userID
+}
+
+// File binds the remaining path.
+// Path: /files/{path...}
+func (ParamService) File(path string) string {
+	return path
+}
+
+// Asset binds an anonymous catch-all.
+// Path: /assets/*
+// Param: name=*
+func (ParamService) Asset(name string) string {
+	return strings.ToUpper(name)
+}
@@ -0,0 +1,46 @@
+package services
+
+import "fmt"
+
+// Profile is decoded from json, xml and forms.
+type Profile struct {
+	Name  string   `json:"name" xml:"name"`
+	Age   int      `json:"age" xml:"age"`
+	Tags  []string `json:"tags" xml:"tag"`
+	Score *float64 `json:"score" xml:"score"`
+}
+
+func (p Profile) String() string {
+	score := "nil"
+	if p.Score != nil {
+		score = fmt.Sprint(*p.Score)
+	}
+
+	return fmt.Sprintf("%s %d %v %s", p.Name, p.Age, p.Tags, score)
+}
+
+// PayloadService decodes payloads according to their content type.
+// Path: /payloads
+type PayloadService struct{}
+
+// Create accepts every supported media type.
+// Path: /
+// Method: POST
+func (PayloadService) Create(p Profile) string {
+	return p.String()
+}
+
+// Import only accepts xml.
+// Path: /xml
+// Method: POST
+// Consumes: application/xml, text/xml
+func (PayloadService) Import(p Profile) string {
+	return p.String()
+}
+
+// Batch decodes a slice.
+// Path: /batch
+// Method: POST
+func (PayloadService) Batch(profiles []Profile) int {
+	return len(profiles)
+}
@@ -0,0 +1,43 @@
+package services
+
+// Row is encoded as a csv row.
+type Row struct {
+	ID   int    `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+}
+
+// Level is encoded as text by its String method.
+type Level int
+
+func (l Level) String() string {
+	return [...]string{"low", "high"}[l]
+}
+
+// ExportService encodes responses according to the Accept header.
+// Path: /export
+type ExportService struct{}
+
+// Rows is encoded as csv or json.
+// Path: /rows
+// Produces: text/csv, application/json
+func (ExportService) Rows() []Row {
+	return []Row{{1, "a"}, {2, "b,c"}}
+}
+
+// Count can be encoded as json, xml and text.
+// Path: /count
+func (ExportService) Count() int {
+	return 42
+}
+
+// Level is encoded by its String method.
+// Path: /level
+func (ExportService) Level() Level {
+	return 1
+}
+
+// Counts is a map, that cannot be encoded as xml.
+// Path: /counts
+func (ExportService) Counts() map[string]int {
+	return map[string]int{"a": 1}
+}
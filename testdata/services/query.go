@@ -0,0 +1,21 @@
+package services
+
+import "fmt"
+
+// QueryService binds query parameters.
+// Path: /query
+type QueryService struct{}
+
+// List reads the page from the query string.
+// Path: /
+// Query: page, size
+func (QueryService) List(page, size int) []int {
+	return []int{page, size}
+}
+
+// Search mixes path and query parameters.
+// Path: /{kind}
+// Query: q, limit
+func (QueryService) Search(kind, q string, limit uint8) string {
+	return fmt.Sprintf("%s %s %d", kind, q, limit)
+}
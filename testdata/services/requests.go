@@ -0,0 +1,50 @@
+package services
+
+import "fmt"
+
+// Filter is the payload of a request struct.
+type Filter struct {
+	Name string `json:"name"`
+}
+
+// SearchRequest binds every part of the request.
+type SearchRequest struct {
+	ID      int64    `path:"id"`
+	Query   string   `query:"q"`
+	Page    int      `query:"page" default:"1"`
+	Tags    []string `query:"tag"`
+	Trace   string   `header:"X-Trace"`
+	Session *string  `cookie:"sid"`
+	Filter  Filter   `body:""`
+	Ignored string
+}
+
+// SignupRequest binds form values.
+type SignupRequest struct {
+	Name  string `form:"name"`
+	Email string `form:"email"`
+}
+
+// RequestService binds request structs.
+// Path: /requests
+type RequestService struct{}
+
+// Search binds a request struct with a payload.
+// Path: /search/{id}
+// Method: POST
+func (RequestService) Search(req *SearchRequest) string {
+	session := "nil"
+	if req.Session != nil {
+		session = *req.Session
+	}
+
+	return fmt.Sprintf("%d %s %d %v %s %s %s [%s]",
+		req.ID, req.Query, req.Page, req.Tags, req.Trace, session, req.Filter.Name, req.Ignored)
+}
+
+// Signup binds a request struct from a form.
+// Path: /signup
+// Method: POST
+func (RequestService) Signup(req SignupRequest) string {
+	return req.Name + " " + req.Email
+}
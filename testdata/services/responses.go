@@ -0,0 +1,56 @@
+package services
+
+import (
+	"net/http"
+	"time"
+)
+
+// Created describes the response of a created resource.
+type Created struct {
+	Name     string       `body:""`
+	Location string       `header:"Location"`
+	Total    int          `header:"X-Total-Count"`
+	Tags     []string     `header:"X-Tag"`
+	Expires  *time.Time   `header:"Expires"`
+	Extra    http.Header  `header:""`
+	Session  *http.Cookie `cookie:""`
+	Status   int          `status:""`
+}
+
+// Empty is a response without a body.
+type Empty struct {
+	Location string `header:"Location"`
+}
+
+// ResponseService returns response structs.
+// Path: /responses
+type ResponseService struct{}
+
+// Create sets the status, headers and cookies of the response.
+// Path: /
+// Method: POST
+// Query: name
+// Status: 201
+func (ResponseService) Create(name string) Created {
+	res := Created{
+		Name:     name,
+		Location: "/responses/" + name,
+		Total:    1,
+		Tags:     []string{"a", "b"},
+		Extra:    http.Header{"X-Extra": {"x"}},
+		Session:  &http.Cookie{Name: "sid", Value: name},
+	}
+
+	if name == "accepted" {
+		res.Status = http.StatusAccepted
+	}
+
+	return res
+}
+
+// Redirect responds without a body.
+// Path: /redirect
+// Status: 303
+func (ResponseService) Redirect() Empty {
+	return Empty{Location: "/responses/target"}
+}
@@ -0,0 +1,23 @@
+package services
+
+// RouteService keeps the trailing slashes of its paths.
+// Path: /routes
+type RouteService struct{}
+
+// Index is registered at the path of the service.
+// Path: /
+func (RouteService) Index() string {
+	return "index"
+}
+
+// Dir is registered with a trailing slash.
+// Path: /dir/
+func (RouteService) Dir() string {
+	return "dir"
+}
+
+// File is registered without a trailing slash.
+// Path: /dir
+func (RouteService) File() string {
+	return "file"
+}
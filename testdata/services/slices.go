@@ -0,0 +1,16 @@
+package services
+
+import "fmt"
+
+// SliceService binds slices.
+// Path: /slices
+type SliceService struct{}
+
+// Split splits path segments and header values at commas and collects
+// repeated query values.
+// Path: /{ids}
+// Query: tags
+// Header: langs=Accept-Language
+func (SliceService) Split(ids []int64, tags []string, langs []string) string {
+	return fmt.Sprintf("%v %v %v", ids, tags, langs)
+}
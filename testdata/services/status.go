@@ -0,0 +1,30 @@
+package services
+
+// StatusService declares status codes of successful responses.
+// Path: /status
+type StatusService struct{}
+
+// Create responds with 201 Created.
+// Path: /
+// Method: POST
+// Status: 201
+func (StatusService) Create() string {
+	return "created"
+}
+
+// Delete defaults to 204 No Content.
+// Path: /
+// Method: DELETE
+func (StatusService) Delete() {}
+
+// Accept responds without a body.
+// Path: /accept
+// Method: POST
+// Status: 202
+func (StatusService) Accept() {}
+
+// Get responds with the implicit 200 OK.
+// Path: /
+func (StatusService) Get() string {
+	return "ok"
+}
@@ -0,0 +1,8 @@
+// Package store declares errors, that are mapped by the services of another
+// package.
+package store
+
+import "errors"
+
+// ErrReadOnly is returned when writing to a read-only store.
+var ErrReadOnly = errors.New("store is read-only")
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Account is validated by tags and its Validate method.
+type Account struct {
+	Name    string     `json:"name" validate:"required,min=2,max=8"`
+	Age     uint8      `json:"age" validate:"max=150"`
+	Ratio   float64    `json:"ratio" validate:"min=0,max=1"`
+	Tags    []string   `json:"tags" validate:"max=3"`
+	Created time.Time  `json:"created" validate:"required"`
+	Parent  *Account   `json:"parent" validate:"required"`
+	Deleted *time.Time `json:"deleted"`
+}
+
+// Validate rejects reserved names.
+func (a Account) Validate() error {
+	if a.Name == "root" {
+		return errors.New("name is reserved")
+	}
+
+	return nil
+}
+
+// PageRequest validates bound fields.
+type PageRequest struct {
+	Page int    `query:"page" default:"1" validate:"min=1"`
+	Sort string `query:"sort" validate:"required"`
+}
+
+// ValidationService validates payloads and request structs.
+// Path: /validation
+type ValidationService struct{}
+
+// Create validates the payload.
+// Path: /
+// Method: POST
+func (ValidationService) Create(account Account) string {
+	return account.Name
+}
+
+// Page validates a request struct.
+// Path: /
+func (ValidationService) Page(req PageRequest) string {
+	return fmt.Sprintf("%d %s", req.Page, req.Sort)
+}